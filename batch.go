@@ -0,0 +1,187 @@
+package squirrel
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lann/builder"
+)
+
+// defaultBatchParams is the bind parameter limit of the statements of a
+// batch insert whose Dialect has none and that has no BatchSize.
+const defaultBatchParams = 999
+
+// batchResult is the sql.Result of the statements of a batch insert.
+type batchResult struct {
+	results []sql.Result
+}
+
+// LastInsertId returns the LastInsertId of the last statement of the batch.
+func (r batchResult) LastInsertId() (int64, error) {
+	if len(r.results) == 0 {
+		return 0, nil
+	}
+	return r.results[len(r.results)-1].LastInsertId()
+}
+
+// RowsAffected returns the sum of the RowsAffected of the statements of the
+// batch.
+func (r batchResult) RowsAffected() (int64, error) {
+	var total int64
+	for _, result := range r.results {
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// valuesParams returns the number of bind parameters of a row of values.
+func valuesParams(row []interface{}) int {
+	n := 0
+	for _, val := range row {
+		if e, ok := val.(expr); ok {
+			_, args, _ := e.ToSql()
+			n += len(args)
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// chunks splits the rows of values of the query into statements of at most
+// BatchRows rows, each within the bind parameter and VALUES row limits of the
+// Dialect.
+func (d *insertData) chunks() ([]*insertData, error) {
+	if d.Select != nil || len(d.Values) == 0 {
+		return []*insertData{d}, nil
+	}
+
+	limit := dialectOr(d.Dialect).maxParams()
+	maxRows := dialectOr(d.Dialect).maxValuesRows()
+	if limit == 0 && d.BatchRows == 0 {
+		limit = defaultBatchParams
+	}
+
+	// The parameters of the rest of the statement, e.g. of its prefixes or
+	// upsert clause, are repeated in each chunk.
+	first := *d
+	first.Values = d.Values[:1]
+	_, args, err := first.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	fixed := len(args) - valuesParams(d.Values[0])
+
+	var chunks []*insertData
+	start, params := 0, fixed
+	for i, row := range d.Values {
+		n := valuesParams(row)
+		if limit > 0 && fixed+n > limit {
+			return nil, fmt.Errorf("row %d of the insert needs %d bind parameters, over the limit of %d", i, fixed+n, limit)
+		}
+		full := (limit > 0 && params+n > limit) ||
+			(d.BatchRows > 0 && uint64(i-start) == d.BatchRows) ||
+			(maxRows > 0 && i-start == maxRows)
+		if full {
+			chunk := *d
+			chunk.Values = d.Values[start:i]
+			chunks = append(chunks, &chunk)
+			start, params = i, fixed
+		}
+		params += n
+	}
+	chunk := *d
+	chunk.Values = d.Values[start:]
+	return append(chunks, &chunk), nil
+}
+
+// execBatch Execs the chunks of the query with exec, returning the results of
+// the chunks run before an error along with it.
+func (d *insertData) execBatch(exec func(Sqlizer) (sql.Result, error)) (sql.Result, error) {
+	chunks, err := d.chunks()
+	if err != nil {
+		return nil, &BuildError{Err: err}
+	}
+
+	result := batchResult{}
+	for i, chunk := range chunks {
+		res, err := exec(chunk)
+		if err != nil {
+			if len(chunks) > 1 {
+				err = fmt.Errorf("insert batch %d of %d: %w", i+1, len(chunks), err)
+			}
+			return result, err
+		}
+		result.results = append(result.results, res)
+	}
+	return result, nil
+}
+
+// BatchSize sets the maximum number of rows of values inserted by each
+// statement of ExecBatch. Chunks are also kept within the bind parameter and
+// VALUES row limits of the Dialect, if it has them.
+func (b InsertBuilder) BatchSize(rows uint64) InsertBuilder {
+	return builder.Set(b, "BatchRows", rows).(InsertBuilder)
+}
+
+// BatchTx makes ExecBatch run its statements in a transaction, so that either
+// all or none of the rows are inserted, when the Runner is a *sql.DB. Runners
+// that cannot begin a transaction, such as a *sql.Tx, run them as they are.
+func (b InsertBuilder) BatchTx() InsertBuilder {
+	return builder.Set(b, "BatchTx", true).(InsertBuilder)
+}
+
+// Chunks splits the rows of values of the query into the statements ExecBatch
+// would run.
+func (b InsertBuilder) Chunks() ([]InsertBuilder, error) {
+	data := builder.GetStruct(b).(insertData)
+	chunks, err := data.chunks()
+	if err != nil {
+		return nil, err
+	}
+	builders := make([]InsertBuilder, len(chunks))
+	for i, chunk := range chunks {
+		builders[i] = builder.Set(b, "Values", chunk.Values).(InsertBuilder)
+	}
+	return builders, nil
+}
+
+// ExecBatch builds and Execs the query with the Runner set by RunWith, split
+// into as many statements as needed to keep each of them within BatchSize rows
+// and the bind parameter limit of the Dialect (999 parameters without a
+// Dialect or BatchSize). SQL Server statements have at most 1000 rows of
+// values, and Oracle ones a single row.
+//
+// The RowsAffected of the result is the sum of those of the statements, and
+// its LastInsertId that of the last one. When a statement fails, the result of
+// the statements run before it is returned along with the error; unless
+// BatchTx is set, their rows stay inserted.
+func (b InsertBuilder) ExecBatch() (sql.Result, error) {
+	data := builder.GetStruct(b).(insertData)
+	if data.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+
+	if db, ok := runnerDB(data.RunWith).(txBeginner); ok && data.BatchTx {
+		tx, err := db.Begin()
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		result, err := data.execBatch(func(s Sqlizer) (sql.Result, error) {
+			return ExecWith(tx, s)
+		})
+		if err != nil {
+			return result, err
+		}
+		return result, tx.Commit()
+	}
+	return data.execBatch(func(s Sqlizer) (sql.Result, error) {
+		return ExecWith(data.RunWith, s)
+	})
+}
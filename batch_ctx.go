@@ -0,0 +1,42 @@
+//go:build go1.8
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lann/builder"
+)
+
+// ExecBatchContext is like ExecBatch, using ctx for the statements.
+func (b InsertBuilder) ExecBatchContext(ctx context.Context) (sql.Result, error) {
+	data := builder.GetStruct(b).(insertData)
+	if data.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	runner, ok := data.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+
+	if db, ok := runnerDB(data.RunWith).(txBeginnerContext); ok && data.BatchTx {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		result, err := data.execBatch(func(s Sqlizer) (sql.Result, error) {
+			return ExecContextWith(ctx, tx, s)
+		})
+		if err != nil {
+			return result, err
+		}
+		return result, tx.Commit()
+	}
+	return data.execBatch(func(s Sqlizer) (sql.Result, error) {
+		return ExecContextWith(ctx, runner, s)
+	})
+}
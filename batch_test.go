@@ -0,0 +1,154 @@
+package squirrel
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// batchExecStub counts the statements it Execs, each affecting a single row,
+// and fails the one numbered failAt.
+type batchExecStub struct {
+	DBStub
+	execs  int
+	failAt int
+}
+
+func (s *batchExecStub) Exec(query string, args ...interface{}) (sql.Result, error) {
+	s.execs++
+	s.DBStub.Exec(query, args...)
+	if s.execs == s.failAt {
+		return nil, StubError
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func batchRows(n int) InsertBuilder {
+	b := Insert("t").Columns("a", "b", "c")
+	for i := 0; i < n; i++ {
+		b = b.Values(i, i, i)
+	}
+	return b
+}
+
+func TestInsertChunks(t *testing.T) {
+	chunks, err := batchRows(1000).Dialect(SQLServer).Chunks()
+	assert.NoError(t, err)
+	if assert.Len(t, chunks, 2) {
+		_, args, _ := chunks[0].ToSql()
+		assert.Len(t, args, 2100)
+		_, args, _ = chunks[1].ToSql()
+		assert.Len(t, args, 900)
+	}
+
+	chunks, err = batchRows(1000).Dialect(SQLServer).BatchSize(400).Chunks()
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 3)
+
+	// Without a Dialect, chunks only follow the BatchSize.
+	chunks, err = batchRows(1000).BatchSize(600).Chunks()
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 2)
+
+	chunks, err = batchRows(1000).Chunks()
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 4)
+
+	chunks, err = batchRows(2).BatchSize(1).Chunks()
+	assert.NoError(t, err)
+	if assert.Len(t, chunks, 2) {
+		sql, args, _ := chunks[1].ToSql()
+		assert.Equal(t, "INSERT INTO t (a,b,c) VALUES (?,?,?)", sql)
+		assert.Equal(t, []interface{}{1, 1, 1}, args)
+	}
+}
+
+func TestInsertChunksFixedParams(t *testing.T) {
+	b := Insert("t").Columns("a", "b").
+		Prefix("WITH x AS (SELECT ?)", 0).
+		Values(1, Expr("? + ?", 2, 3)).
+		Values(4, 5).
+		OnConflict("a").DoUpdateSet("b", 6).
+		Dialect(SQLite)
+	for i := 0; i < 497; i++ {
+		b = b.Values(i, i)
+	}
+
+	// 2 fixed parameters, 3 for the first row and 2 for the others.
+	chunks, err := b.Chunks()
+	assert.NoError(t, err)
+	if assert.Len(t, chunks, 2) {
+		sql, args, _ := chunks[0].ToSql()
+		assert.Len(t, args, 999)
+		assert.Contains(t, sql, "ON CONFLICT (a) DO UPDATE SET b = ?")
+		_, args, _ = chunks[1].ToSql()
+		assert.Len(t, args, 4)
+	}
+
+	_, err = Insert("t").Values(make([]interface{}, 2101)...).Dialect(SQLServer).Chunks()
+	assert.EqualError(t, err, "row 0 of the insert needs 2101 bind parameters, over the limit of 2100")
+}
+
+func TestInsertChunksMaxRows(t *testing.T) {
+	b := Insert("t").Columns("a").Dialect(SQLServer)
+	for i := 0; i < 1500; i++ {
+		b = b.Values(i)
+	}
+	chunks, err := b.Chunks()
+	assert.NoError(t, err)
+	if assert.Len(t, chunks, 2) {
+		_, args, _ := chunks[0].ToSql()
+		assert.Len(t, args, 1000)
+		_, args, _ = chunks[1].ToSql()
+		assert.Len(t, args, 500)
+	}
+
+	_, _, err = b.ToSql()
+	assert.EqualError(t, err, "insert has 1500 rows of values, over the sqlserver dialect limit of 1000; use ExecBatch")
+
+	chunks, err = batchRows(3).Dialect(Oracle).Chunks()
+	assert.NoError(t, err)
+	if assert.Len(t, chunks, 3) {
+		sql, args, err := chunks[2].ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "INSERT INTO t (a,b,c) VALUES (:1,:2,:3)", sql)
+		assert.Equal(t, []interface{}{2, 2, 2}, args)
+	}
+
+	_, _, err = batchRows(2).Dialect(Oracle).ToSql()
+	assert.EqualError(t, err, "insert has 2 rows of values, over the oracle dialect limit of 1; use ExecBatch")
+}
+
+func TestInsertChunksSelect(t *testing.T) {
+	b := Insert("t").Select(Select("a").From("u"))
+	chunks, err := b.BatchSize(1).Chunks()
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 1)
+}
+
+func TestInsertExecBatch(t *testing.T) {
+	db := &batchExecStub{}
+	result, err := batchRows(5).BatchSize(2).RunWith(db).ExecBatch()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, db.execs)
+	assert.Equal(t, "INSERT INTO t (a,b,c) VALUES (?,?,?)", db.LastExecSql)
+	rows, err := result.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), rows)
+
+	db = &batchExecStub{failAt: 2}
+	result, err = batchRows(5).BatchSize(2).RunWith(db).ExecBatch()
+	assert.EqualError(t, err, "insert batch 2 of 3: "+StubError.Error())
+	assert.True(t, errors.Is(err, StubError))
+	rows, _ = result.RowsAffected()
+	assert.Equal(t, int64(1), rows)
+
+	_, err = batchRows(5).ExecBatch()
+	assert.Equal(t, RunnerNotSet, err)
+
+	_, err = Insert("t").Values(make([]interface{}, 2101)...).Dialect(SQLServer).RunWith(db).ExecBatch()
+	assert.True(t, IsConfigError(err))
+}
@@ -0,0 +1,319 @@
+package squirrel
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+
+	"github.com/lann/builder"
+)
+
+// compoundPart is a SELECT statement of a compound query together with the
+// set operator joining it to the previous one.
+type compoundPart struct {
+	op    string
+	query SelectBuilder
+}
+
+type compoundData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	RunWith           BaseRunner
+	Prefixes          exprs
+	Parts             []compoundPart
+	OrderByParts      []Sqlizer
+	Limit             string
+	Offset            string
+	Suffixes          exprs
+	statementOptions
+}
+
+func (d *compoundData) Exec() (sql.Result, error) {
+	return execWithRunner(d.RunWith, d)
+}
+
+func (d *compoundData) Query() (*sql.Rows, error) {
+	return queryWithRunner(d.RunWith, d)
+}
+
+func (d *compoundData) QueryRow() RowScanner {
+	return queryRowWithRunner(d.RunWith, d)
+}
+
+func (d *compoundData) ToSql() (sqlStr string, args []interface{}, err error) {
+	sqlStr, args, err = d.toSql()
+	if err != nil {
+		return
+	}
+	if d.CheckParams {
+		if err = d.checkParams(sqlStr, args); err != nil {
+			return
+		}
+	}
+
+	sqlStr, args, err = finalizePlaceholders(d.PlaceholderFormat, sqlStr, args)
+	return
+}
+
+func (d *compoundData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
+	return d.toSql()
+}
+
+func (d *compoundData) toSql() (sqlStr string, args []interface{}, err error) {
+	if len(d.Parts) < 2 {
+		err = fmt.Errorf("compound statements must combine at least two select statements")
+		return
+	}
+	if d.StrictIdents {
+		if err = newIdentChecker(d.Dialect).checkParts(d.OrderByParts, identOrder); err != nil {
+			return
+		}
+	}
+
+	sql := &bytes.Buffer{}
+
+	if len(d.Prefixes) > 0 {
+		args, err = d.Prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
+		sql.WriteString(" ")
+	}
+
+	// The prefixes stay in front of the queries wrapping a compound query
+	// paginated with ROW_NUMBER.
+	headLen, headArgs := sql.Len(), len(args)
+
+	for i, p := range d.Parts {
+		if i > 0 {
+			sql.WriteString(" ")
+			sql.WriteString(p.op)
+			sql.WriteString(" ")
+		}
+
+		// Members are rendered raw so that the placeholders of all of them
+		// are numbered once, by the compound statement's PlaceholderFormat.
+		partSql, partArgs, err := p.query.toSqlRaw()
+		if err != nil {
+			return "", nil, err
+		}
+
+		// A member with its own ORDER BY or LIMIT must be parenthesized, or
+		// those clauses would apply to the combined result.
+		data := builder.GetStruct(p.query).(selectData)
+		if len(data.OrderByParts) > 0 || len(data.Limit) > 0 || len(data.Offset) > 0 {
+			partSql = fmt.Sprintf("(%s)", partSql)
+		}
+
+		sql.WriteString(partSql)
+		args = append(args, partArgs...)
+	}
+
+	pagination := dialectOr(d.Dialect).pagination()
+	rowNumber := pagination == paginationRowNumber && (len(d.Limit) > 0 || len(d.Offset) > 0)
+
+	var orderBySql string
+	var orderByArgs []interface{}
+	if len(d.OrderByParts) > 0 {
+		orderBy := &bytes.Buffer{}
+		orderByArgs, err = appendToSqlDialect(d.Dialect, d.OrderByParts, orderBy, ", ", nil)
+		if err != nil {
+			return
+		}
+		orderBySql = orderBy.String()
+	}
+
+	if rowNumber {
+		// The combined result is numbered from the outside, so its ORDER BY
+		// can only refer to result columns, as it must anyway.
+		body := fmt.Sprintf("SELECT q.*, %s FROM (%s) q", rowNumberColumn(orderBySql), sql.String()[headLen:])
+		// The ORDER BY of the row number comes before the wrapped query.
+		args = append(append(args[:headArgs:headArgs], orderByArgs...), args[headArgs:]...)
+		sql.Truncate(headLen)
+		sql.WriteString(body)
+	} else {
+		if len(orderBySql) > 0 {
+			sql.WriteString(" ORDER BY ")
+			sql.WriteString(orderBySql)
+			args = append(args, orderByArgs...)
+		} else if pagination == paginationTop && (len(d.Limit) > 0 || len(d.Offset) > 0) {
+			// SQL Server only accepts OFFSET after an ORDER BY clause.
+			sql.WriteString(" ORDER BY (SELECT NULL)")
+		}
+
+		if err = appendPaginationToSql(sql, pagination, d.Limit, d.Offset); err != nil {
+			return
+		}
+	}
+
+	if len(d.Suffixes) > 0 {
+		sql.WriteString(" ")
+		args, err = d.Suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	sqlStr = sql.String()
+
+	if rowNumber {
+		var body string
+		body, err = filterRowNumber(sqlStr[headLen:], d.Limit, d.Offset)
+		sqlStr = sqlStr[:headLen] + body
+	}
+	return
+}
+
+// Builder
+
+// CompoundSelectBuilder builds SQL statements combining SELECT statements with
+// the UNION, UNION ALL, INTERSECT and EXCEPT set operators.
+type CompoundSelectBuilder builder.Builder
+
+func init() {
+	builder.Register(CompoundSelectBuilder{}, compoundData{})
+}
+
+// Format methods
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+//
+// The PlaceholderFormat of the combined SelectBuilders is ignored.
+func (b CompoundSelectBuilder) PlaceholderFormat(f PlaceholderFormat) CompoundSelectBuilder {
+	return builder.Set(b, "PlaceholderFormat", f).(CompoundSelectBuilder)
+}
+
+func (b CompoundSelectBuilder) debugPlaceholder() string {
+	data := builder.GetStruct(b).(compoundData)
+	return debugPlaceholder(data.PlaceholderFormat)
+}
+
+// Dialect sets the Dialect of the query, along with its PlaceholderFormat.
+func (b CompoundSelectBuilder) Dialect(d Dialect) CompoundSelectBuilder {
+	return setDialect(b, d).(CompoundSelectBuilder)
+}
+
+// Runner methods
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b CompoundSelectBuilder) RunWith(runner BaseRunner) CompoundSelectBuilder {
+	return setRunWith(b, runner).(CompoundSelectBuilder)
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b CompoundSelectBuilder) Exec() (sql.Result, error) {
+	data := builder.GetStruct(b).(compoundData)
+	return data.Exec()
+}
+
+// Query builds and Querys the query with the Runner set by RunWith.
+func (b CompoundSelectBuilder) Query() (*sql.Rows, error) {
+	data := builder.GetStruct(b).(compoundData)
+	return data.Query()
+}
+
+// QueryRow builds and QueryRows the query with the Runner set by RunWith.
+func (b CompoundSelectBuilder) QueryRow() RowScanner {
+	data := builder.GetStruct(b).(compoundData)
+	return data.QueryRow()
+}
+
+// Scan is a shortcut for QueryRow().Scan.
+func (b CompoundSelectBuilder) Scan(dest ...interface{}) error {
+	return b.QueryRow().Scan(dest...)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b CompoundSelectBuilder) ToSql() (string, []interface{}, error) {
+	data := builder.GetStruct(b).(compoundData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b CompoundSelectBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+func (b CompoundSelectBuilder) toSqlRaw() (string, []interface{}, error) {
+	data := builder.GetStruct(b).(compoundData)
+	return data.toSqlRaw()
+}
+
+// Prefix adds an expression to the beginning of the query
+func (b CompoundSelectBuilder) Prefix(sql string, args ...interface{}) CompoundSelectBuilder {
+	return builder.Append(b, "Prefixes", Expr(sql, args...)).(CompoundSelectBuilder)
+}
+
+func (b CompoundSelectBuilder) combine(op string, selects ...SelectBuilder) CompoundSelectBuilder {
+	for _, s := range selects {
+		b = builder.Append(b, "Parts", compoundPart{op: op, query: s}).(CompoundSelectBuilder)
+	}
+	return b
+}
+
+// Union adds SELECT statements combined with UNION to the query.
+func (b CompoundSelectBuilder) Union(selects ...SelectBuilder) CompoundSelectBuilder {
+	return b.combine("UNION", selects...)
+}
+
+// UnionAll adds SELECT statements combined with UNION ALL to the query.
+func (b CompoundSelectBuilder) UnionAll(selects ...SelectBuilder) CompoundSelectBuilder {
+	return b.combine("UNION ALL", selects...)
+}
+
+// Intersect adds SELECT statements combined with INTERSECT to the query.
+func (b CompoundSelectBuilder) Intersect(selects ...SelectBuilder) CompoundSelectBuilder {
+	return b.combine("INTERSECT", selects...)
+}
+
+// Except adds SELECT statements combined with EXCEPT to the query.
+func (b CompoundSelectBuilder) Except(selects ...SelectBuilder) CompoundSelectBuilder {
+	return b.combine("EXCEPT", selects...)
+}
+
+// OrderByClause adds ORDER BY clause to the combined result.
+func (b CompoundSelectBuilder) OrderByClause(pred interface{}, args ...interface{}) CompoundSelectBuilder {
+	return builder.Append(b, "OrderByParts", newPart(pred, args...)).(CompoundSelectBuilder)
+}
+
+// OrderBy adds ORDER BY expressions to the combined result.
+func (b CompoundSelectBuilder) OrderBy(orderBys ...string) CompoundSelectBuilder {
+	for _, orderBy := range orderBys {
+		b = b.OrderByClause(orderBy)
+	}
+
+	return b
+}
+
+// Limit sets a LIMIT clause on the combined result.
+func (b CompoundSelectBuilder) Limit(limit uint64) CompoundSelectBuilder {
+	return builder.Set(b, "Limit", fmt.Sprintf("%d", limit)).(CompoundSelectBuilder)
+}
+
+// RemoveLimit removes LIMIT clause.
+func (b CompoundSelectBuilder) RemoveLimit() CompoundSelectBuilder {
+	return builder.Delete(b, "Limit").(CompoundSelectBuilder)
+}
+
+// Offset sets a OFFSET clause on the combined result.
+func (b CompoundSelectBuilder) Offset(offset uint64) CompoundSelectBuilder {
+	return builder.Set(b, "Offset", fmt.Sprintf("%d", offset)).(CompoundSelectBuilder)
+}
+
+// RemoveOffset removes OFFSET clause.
+func (b CompoundSelectBuilder) RemoveOffset() CompoundSelectBuilder {
+	return builder.Delete(b, "Offset").(CompoundSelectBuilder)
+}
+
+// Suffix adds an expression to the end of the query
+func (b CompoundSelectBuilder) Suffix(sql string, args ...interface{}) CompoundSelectBuilder {
+	return builder.Append(b, "Suffixes", Expr(sql, args...)).(CompoundSelectBuilder)
+}
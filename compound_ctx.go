@@ -0,0 +1,46 @@
+//go:build go1.8
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lann/builder"
+)
+
+func (d *compoundData) ExecContext(ctx context.Context) (sql.Result, error) {
+	return execContextWithRunner(ctx, d.RunWith, d)
+}
+
+func (d *compoundData) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	return queryContextWithRunner(ctx, d.RunWith, d)
+}
+
+func (d *compoundData) QueryRowContext(ctx context.Context) RowScanner {
+	return queryRowContextWithRunner(ctx, d.RunWith, d)
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b CompoundSelectBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	data := builder.GetStruct(b).(compoundData)
+	return data.ExecContext(ctx)
+}
+
+// QueryContext builds and QueryContexts the query with the Runner set by RunWith.
+func (b CompoundSelectBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	data := builder.GetStruct(b).(compoundData)
+	return data.QueryContext(ctx)
+}
+
+// QueryRowContext builds and QueryRowContexts the query with the Runner set by RunWith.
+func (b CompoundSelectBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	data := builder.GetStruct(b).(compoundData)
+	return data.QueryRowContext(ctx)
+}
+
+// ScanContext is a shortcut for QueryRowContext().Scan.
+func (b CompoundSelectBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return b.QueryRowContext(ctx).Scan(dest...)
+}
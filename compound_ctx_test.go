@@ -0,0 +1,41 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompoundSelectBuilderContextRunners(t *testing.T) {
+	db := &DBStub{}
+	b := Union(Select("a").From("x"), Select("a").From("y")).RunWith(db)
+
+	expectedSql := "SELECT a FROM x UNION SELECT a FROM y"
+
+	b.ExecContext(ctx)
+	assert.Equal(t, expectedSql, db.LastExecSql)
+
+	b.QueryContext(ctx)
+	assert.Equal(t, expectedSql, db.LastQuerySql)
+
+	b.QueryRowContext(ctx)
+	assert.Equal(t, expectedSql, db.LastQueryRowSql)
+
+	err := b.ScanContext(ctx)
+	assert.NoError(t, err)
+}
+
+func TestCompoundSelectBuilderContextNoRunner(t *testing.T) {
+	b := Union(Select("a").From("x"), Select("a").From("y"))
+
+	_, err := b.ExecContext(ctx)
+	assert.Equal(t, RunnerNotSet, err)
+
+	_, err = b.QueryContext(ctx)
+	assert.Equal(t, RunnerNotSet, err)
+
+	err = b.ScanContext(ctx)
+	assert.Equal(t, RunnerNotSet, err)
+}
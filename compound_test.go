@@ -0,0 +1,126 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompoundSelectBuilderToSql(t *testing.T) {
+	b := Union(
+		Select("id", "name").From("users").Where("active = ?", true),
+		Select("id", "name").From("admins").Where(Eq{"level": []int{1, 2}}),
+	).
+		UnionAll(Select("id", "name").From("guests").Where("created_at > ?", 3)).
+		Intersect(Select("id", "name").From("members")).
+		Except(Select("id", "name").From("banned").Where("until > ?", 4)).
+		Prefix("/* ? */", 0).
+		OrderBy("name").
+		OrderByClause("id = ? DESC", 5).
+		Limit(10).
+		Offset(20).
+		Suffix("-- ?", 6)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "/* ? */ " +
+		"SELECT id, name FROM users WHERE active = ? " +
+		"UNION SELECT id, name FROM admins WHERE level IN (?,?) " +
+		"UNION ALL SELECT id, name FROM guests WHERE created_at > ? " +
+		"INTERSECT SELECT id, name FROM members " +
+		"EXCEPT SELECT id, name FROM banned WHERE until > ? " +
+		"ORDER BY name, id = ? DESC LIMIT 10 OFFSET 20 -- ?"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{0, true, 1, 2, 3, 4, 5, 6}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestCompoundSelectBuilderDollarPlaceholders(t *testing.T) {
+	a := Select("id").From("a").Where("x = ?", 1).PlaceholderFormat(Dollar)
+	b := Select("id").From("b").Where("y = ?", 2).PlaceholderFormat(Dollar)
+
+	sql, args, err := UnionAll(a, b).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM a WHERE x = $1 UNION ALL SELECT id FROM b WHERE y = $2", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, _, err = StatementBuilder.PlaceholderFormat(Colon).UnionAll(a, b).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM a WHERE x = :1 UNION ALL SELECT id FROM b WHERE y = :2", sql)
+}
+
+func TestCompoundSelectBuilderParenthesizesLimitedMembers(t *testing.T) {
+	a := Select("id").From("a").OrderBy("id DESC").Limit(1)
+	b := Select("id").From("b")
+
+	sql, _, err := Union(a, b).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(SELECT id FROM a ORDER BY id DESC LIMIT 1) UNION SELECT id FROM b", sql)
+}
+
+func TestCompoundSelectBuilderNested(t *testing.T) {
+	tree := UnionAll(
+		Select("id", "parent_id").From("nodes").Where(Eq{"id": 1}),
+		Select("n.id", "n.parent_id").From("nodes n").Join("tree ON n.parent_id = tree.id").Where("n.depth < ?", 5),
+	).PlaceholderFormat(Dollar)
+
+	sql, args, err := Select("id").WithRecursive("tree", tree).From("tree").Where("id <> ?", 7).
+		PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "WITH RECURSIVE tree AS (SELECT id, parent_id FROM nodes WHERE id = $1 UNION ALL " +
+		"SELECT n.id, n.parent_id FROM nodes n JOIN tree ON n.parent_id = tree.id WHERE n.depth < $2) " +
+		"SELECT id FROM tree WHERE id <> $3"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 5, 7}, args)
+
+	sql, args, err = Select("*").From("c").Where(Eq{"kind": 0}).Where(ConcatExpr("id IN (", Union(
+		Select("id").From("a").Where("x = ?", 1),
+		Select("id").From("b").Where("y = ?", 2),
+	), ")")).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM c WHERE kind = $1 AND id IN (SELECT id FROM a WHERE x = $2 UNION SELECT id FROM b WHERE y = $3)", sql)
+	assert.Equal(t, []interface{}{0, 1, 2}, args)
+}
+
+func TestCompoundSelectBuilderToSqlErr(t *testing.T) {
+	_, _, err := Union(Select("id").From("a")).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Union(Select("id").From("a"), Select()).ToSql()
+	assert.Error(t, err)
+}
+
+func TestCompoundSelectBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	b := Union(Select("a").From("x"), Select("a").From("y")).RunWith(db)
+
+	expectedSql := "SELECT a FROM x UNION SELECT a FROM y"
+
+	b.Exec()
+	assert.Equal(t, expectedSql, db.LastExecSql)
+
+	b.Query()
+	assert.Equal(t, expectedSql, db.LastQuerySql)
+
+	b.QueryRow()
+	assert.Equal(t, expectedSql, db.LastQueryRowSql)
+
+	err := b.Scan()
+	assert.NoError(t, err)
+}
+
+func TestCompoundSelectBuilderNoRunner(t *testing.T) {
+	b := Union(Select("a").From("x"), Select("a").From("y"))
+
+	_, err := b.Exec()
+	assert.Equal(t, RunnerNotSet, err)
+
+	_, err = b.Query()
+	assert.Equal(t, RunnerNotSet, err)
+
+	err = b.Scan()
+	assert.Equal(t, RunnerNotSet, err)
+}
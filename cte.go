@@ -0,0 +1,63 @@
+package squirrel
+
+import (
+	"fmt"
+	"io"
+)
+
+// cte is a single named query of a WITH clause.
+type cte struct {
+	name      string
+	query     Sqlizer
+	recursive bool
+}
+
+type ctes []cte
+
+// AppendToSql writes the WITH clause, including a trailing space, to w.
+//
+// A single recursive CTE turns the whole clause into WITH RECURSIVE, as the
+// keyword applies to the clause rather than to the individual queries.
+func (cs ctes) AppendToSql(w io.Writer, args []interface{}) ([]interface{}, error) {
+	if len(cs) == 0 {
+		return args, nil
+	}
+
+	keyword := "WITH "
+	for _, c := range cs {
+		if c.recursive {
+			keyword = "WITH RECURSIVE "
+			break
+		}
+	}
+	if _, err := io.WriteString(w, keyword); err != nil {
+		return nil, err
+	}
+
+	for i, c := range cs {
+		if len(c.name) == 0 {
+			return nil, fmt.Errorf("common table expressions must have a name")
+		}
+		if c.query == nil {
+			return nil, fmt.Errorf("common table expression %s has no query", c.name)
+		}
+
+		querySql, queryArgs, err := nestedToSql(c.query)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(w, ", "); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s AS (%s)", c.name, querySql); err != nil {
+			return nil, err
+		}
+		args = append(args, queryArgs...)
+	}
+
+	_, err := io.WriteString(w, " ")
+	return args, err
+}
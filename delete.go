@@ -3,6 +3,7 @@ package squirrel
 import (
 	"bytes"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -11,21 +12,33 @@ import (
 
 type deleteData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
 	RunWith           BaseRunner
 	Prefixes          exprs
 	From              string
+	Targets           []string
+	Using             []Sqlizer
+	Joins             []Sqlizer
 	WhereParts        []Sqlizer
 	OrderBys          []string
 	Limit             string
 	Offset            string
+	Returning         []string
 	Suffixes          exprs
+	AllRows           bool
+	statementOptions
 }
 
 func (d *deleteData) Exec() (sql.Result, error) {
-	if d.RunWith == nil {
-		return nil, RunnerNotSet
-	}
-	return ExecWith(d.RunWith, d)
+	return execWithRunner(d.RunWith, d)
+}
+
+func (d *deleteData) Query() (*sql.Rows, error) {
+	return queryWithRunner(d.RunWith, d)
+}
+
+func (d *deleteData) QueryRow() RowScanner {
+	return queryRowWithRunner(d.RunWith, d)
 }
 
 func (d *deleteData) ToSql() (sqlStr string, args []interface{}, err error) {
@@ -34,48 +47,191 @@ func (d *deleteData) ToSql() (sqlStr string, args []interface{}, err error) {
 		return
 	}
 
+	pagination := dialectOr(d.Dialect).pagination()
+	top, err := mutationTop(dialectOr(d.Dialect), d.Limit, d.Offset)
+	if err != nil {
+		return
+	}
+	returning, output, err := returningClauses(d.Dialect, d.Returning, outputDeleted)
+	if err != nil {
+		return
+	}
+	if err = d.checkTables(); err != nil {
+		return
+	}
+	if d.StrictIdents {
+		if err = d.checkIdents(); err != nil {
+			return
+		}
+	}
+
 	sql := &bytes.Buffer{}
 
 	if len(d.Prefixes) > 0 {
-		args, _ = d.Prefixes.AppendToSql(sql, " ", args)
+		args, err = d.Prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
-	sql.WriteString("DELETE FROM ")
-	sql.WriteString(d.From)
+	sql.WriteString("DELETE ")
+	sql.WriteString(top)
 
-	if len(d.WhereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSql(d.WhereParts, sql, " AND ", args)
-		if err != nil {
-			return
+	if d.joined() {
+		// DELETE t FROM t JOIN u ON ...
+		sql.WriteString(strings.Join(d.targets(), ", "))
+		sql.WriteString(" ")
+		if len(output) > 0 {
+			sql.WriteString(output)
+			sql.WriteString(" ")
+		}
+		sql.WriteString("FROM ")
+		sql.WriteString(d.From)
+		if len(d.Joins) > 0 {
+			sql.WriteString(" ")
+			args, err = appendToSqlDialect(d.Dialect, d.Joins, sql, " ", args)
+			if err != nil {
+				return
+			}
 		}
+	} else {
+		sql.WriteString("FROM ")
+		sql.WriteString(d.From)
+
+		if len(output) > 0 {
+			sql.WriteString(" ")
+			sql.WriteString(output)
+		}
+
+		if len(d.Using) > 0 {
+			sql.WriteString(" USING ")
+			args, err = appendToSqlDialect(d.Dialect, d.Using, sql, ", ", args)
+			if err != nil {
+				return
+			}
+			if len(d.Joins) > 0 {
+				sql.WriteString(" ")
+				args, err = appendToSqlDialect(d.Dialect, d.Joins, sql, " ", args)
+				if err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	where := &bytes.Buffer{}
+	args, err = appendToSqlDialect(d.Dialect, d.WhereParts, where, " AND ", args)
+	if err != nil {
+		return
+	}
+	if !d.AllRows && isAlwaysTrue(d.WhereParts) {
+		err = fmt.Errorf("delete from %s: %w; call AllRows to allow it", d.From, UnfilteredMutation)
+		return
+	}
+	if where.Len() > 0 {
+		sql.WriteString(" WHERE ")
+		where.WriteTo(sql)
 	}
 
+	sql.WriteString(returning)
+
 	if len(d.OrderBys) > 0 {
 		sql.WriteString(" ORDER BY ")
 		sql.WriteString(strings.Join(d.OrderBys, ", "))
 	}
 
-	if len(d.Limit) > 0 {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(d.Limit)
-	}
-
-	if len(d.Offset) > 0 {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(d.Offset)
+	if pagination == paginationLimitOffset {
+		if err = appendPaginationToSql(sql, pagination, d.Limit, d.Offset); err != nil {
+			return
+		}
 	}
 
 	if len(d.Suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = d.Suffixes.AppendToSql(sql, " ", args)
+		args, err = d.Suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if d.CheckParams {
+		if err = d.checkParams(sql.String(), args); err != nil {
+			return
+		}
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr, args, err = finalizePlaceholders(d.PlaceholderFormat, sql.String(), args)
 	return
 }
 
+// joined reports whether the query is a multi-table DELETE naming the tables
+// to delete from before its FROM clause.
+func (d *deleteData) joined() bool {
+	return len(d.Using) == 0 && (len(d.Joins) > 0 || len(d.Targets) > 0)
+}
+
+// targets returns the tables rows are deleted from by a joined query: Targets,
+// or else the From table, by its alias if it has one.
+func (d *deleteData) targets() []string {
+	if len(d.Targets) > 0 {
+		return d.Targets
+	}
+	fields := strings.Fields(d.From)
+	if len(fields) == 0 {
+		return []string{d.From}
+	}
+	return fields[len(fields)-1:]
+}
+
+// checkTables checks that the Dialect supports the way the query references
+// other tables, with Using or with joins.
+func (d *deleteData) checkTables() error {
+	if len(d.Using) == 0 && len(d.Joins) == 0 && len(d.Targets) == 0 {
+		return nil
+	}
+	if len(d.Using) > 0 && len(d.Targets) > 0 {
+		return errors.New("delete statements cannot have both Targets and Using")
+	}
+	switch dialectOr(d.Dialect).multiTableDelete() {
+	case multiTableNone:
+		return fmt.Errorf("%s dialect does not support deletes referencing other tables", d.Dialect.Name())
+	case multiTableFrom:
+		if len(d.Targets) > 0 {
+			return fmt.Errorf("%s dialect does not support deleting from several tables", d.Dialect.Name())
+		}
+		if len(d.Using) == 0 {
+			return fmt.Errorf("%s dialect does not support DELETE ... JOIN; join the tables of Using instead", d.Dialect.Name())
+		}
+	case multiTableJoin:
+		if len(d.Using) > 0 {
+			return fmt.Errorf("%s dialect does not support DELETE ... USING; use Join instead", d.Dialect.Name())
+		}
+	}
+	return nil
+}
+
+// checkIdents checks the identifiers of the query for strict mode.
+func (d *deleteData) checkIdents() error {
+	c := newIdentChecker(d.Dialect)
+	if err := c.check(d.From, identTable); err != nil {
+		return err
+	}
+	if err := c.checkAll(d.Targets, identName); err != nil {
+		return err
+	}
+	if err := c.checkParts(d.Using, identTable); err != nil {
+		return err
+	}
+	if err := c.checkExprs(d.Joins); err != nil {
+		return err
+	}
+	if err := c.checkExprs(d.WhereParts); err != nil {
+		return err
+	}
+	return c.checkAll(d.OrderBys, identOrder)
+}
+
 // Builder
 
 // DeleteBuilder builds SQL DELETE statements.
@@ -93,6 +249,16 @@ func (b DeleteBuilder) PlaceholderFormat(f PlaceholderFormat) DeleteBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(DeleteBuilder)
 }
 
+func (b DeleteBuilder) debugPlaceholder() string {
+	data := builder.GetStruct(b).(deleteData)
+	return debugPlaceholder(data.PlaceholderFormat)
+}
+
+// Dialect sets the Dialect of the query, along with its PlaceholderFormat.
+func (b DeleteBuilder) Dialect(d Dialect) DeleteBuilder {
+	return setDialect(b, d).(DeleteBuilder)
+}
+
 // Runner methods
 
 // RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
@@ -106,6 +272,49 @@ func (b DeleteBuilder) Exec() (sql.Result, error) {
 	return data.Exec()
 }
 
+// Query builds and Querys the query with the Runner set by RunWith.
+func (b DeleteBuilder) Query() (*sql.Rows, error) {
+	data := builder.GetStruct(b).(deleteData)
+	return data.Query()
+}
+
+// QueryRow builds and QueryRows the query with the Runner set by RunWith.
+func (b DeleteBuilder) QueryRow() RowScanner {
+	data := builder.GetStruct(b).(deleteData)
+	return data.QueryRow()
+}
+
+// Scan is a shortcut for QueryRow().Scan.
+func (b DeleteBuilder) Scan(dest ...interface{}) error {
+	return b.QueryRow().Scan(dest...)
+}
+
+// ScanStruct builds and Querys the query with the Runner set by RunWith and
+// scans the first returned row into dest.
+//
+// See SelectBuilder.ScanStruct for the values dest can point to.
+func (b DeleteBuilder) ScanStruct(dest interface{}) error {
+	data := builder.GetStruct(b).(deleteData)
+	rows, err := data.Query()
+	if err != nil {
+		return err
+	}
+	return scanOne(rows, dest, false)
+}
+
+// ScanAll builds and Querys the query with the Runner set by RunWith and
+// scans all the returned rows into dest, a pointer to a slice.
+//
+// See SelectBuilder.ScanAll for the slices dest can point to.
+func (b DeleteBuilder) ScanAll(dest interface{}) error {
+	data := builder.GetStruct(b).(deleteData)
+	rows, err := data.Query()
+	if err != nil {
+		return err
+	}
+	return scanAll(rows, dest, false)
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -124,6 +333,64 @@ func (b DeleteBuilder) From(from string) DeleteBuilder {
 	return builder.Set(b, "From", from).(DeleteBuilder)
 }
 
+// Targets sets the tables rows are deleted from by a multi-table DELETE with
+// joins, as in MySQL's
+//   DELETE o, i FROM orders o JOIN items i ON i.order_id = o.id WHERE ...
+// Without Targets, rows are deleted from the From table only, named by its
+// alias if it has one.
+func (b DeleteBuilder) Targets(tables ...string) DeleteBuilder {
+	return builder.Extend(b, "Targets", tables).(DeleteBuilder)
+}
+
+// Using adds tables to the USING clause of the query, listing the other tables
+// the WHERE clause can reference, as in PostgreSQL's
+//   DELETE FROM orders USING customers c WHERE orders.customer_id = c.id AND ...
+// The joins of the query are rendered after it. The MySQL and SQL Server
+// dialects do not support it; use Join instead.
+func (b DeleteBuilder) Using(tables ...string) DeleteBuilder {
+	for _, table := range tables {
+		b = builder.Append(b, "Using", newPart(table)).(DeleteBuilder)
+	}
+	return b
+}
+
+// UsingSelect adds a subquery to the USING clause of the query.
+func (b DeleteBuilder) UsingSelect(using SelectBuilder, alias string) DeleteBuilder {
+	return builder.Append(b, "Using", derivedTable{query: using, alias: alias}).(DeleteBuilder)
+}
+
+// JoinClause adds a join clause to the query.
+//
+// Without Using, the query deletes from the tables joined to its From table
+// in the form DELETE t FROM t JOIN u ON ...; see Targets. With Using, joins
+// join the tables of the USING clause.
+func (b DeleteBuilder) JoinClause(pred interface{}, args ...interface{}) DeleteBuilder {
+	return builder.Append(b, "Joins", newPart(pred, args...)).(DeleteBuilder)
+}
+
+// Join adds a JOIN clause to the query.
+func (b DeleteBuilder) Join(join string, rest ...interface{}) DeleteBuilder {
+	return b.JoinClause("JOIN "+join, rest...)
+}
+
+// LeftJoin adds a LEFT JOIN clause to the query.
+func (b DeleteBuilder) LeftJoin(join string, rest ...interface{}) DeleteBuilder {
+	return b.JoinClause("LEFT JOIN "+join, rest...)
+}
+
+// RightJoin adds a RIGHT JOIN clause to the query.
+func (b DeleteBuilder) RightJoin(join string, rest ...interface{}) DeleteBuilder {
+	return b.JoinClause("RIGHT JOIN "+join, rest...)
+}
+
+// JoinSelect adds a JOIN clause joining a subquery aliased as alias on the
+// condition on.
+//
+// See UpdateBuilder.JoinSelect for an example.
+func (b DeleteBuilder) JoinSelect(join SelectBuilder, alias string, on string, args ...interface{}) DeleteBuilder {
+	return b.JoinClause(ConcatExpr("JOIN ", derivedTable{query: join, alias: alias}, " ON ", Expr(on, args...)))
+}
+
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
@@ -146,19 +413,25 @@ func (b DeleteBuilder) Offset(offset uint64) DeleteBuilder {
 	return builder.Set(b, "Offset", fmt.Sprintf("%d", offset)).(DeleteBuilder)
 }
 
-// Suffix adds an expression to the end of the query
-func (b DeleteBuilder) Suffix(sql string, args ...interface{}) DeleteBuilder {
-	return builder.Append(b, "Suffixes", Expr(sql, args...)).(DeleteBuilder)
+// AllRows allows the query to delete every row of the table, which ToSql
+// otherwise refuses to do when the query has no WHERE clause or only always
+// true ones, such as an empty Eq.
+func (b DeleteBuilder) AllRows() DeleteBuilder {
+	return builder.Set(b, "AllRows", true).(DeleteBuilder)
 }
 
-func (b DeleteBuilder) Query() (*sql.Rows, error) {
-	data := builder.GetStruct(b).(deleteData)
-	return data.Query()
+// Returning adds a clause returning columns of the deleted rows, e.g. to
+// fetch generated IDs, rendered as a RETURNING clause or, on SQL Server, as an
+// OUTPUT clause. It returns an error on dialects supporting neither, such as
+// MySQL.
+//
+// Run the statement with Query, QueryRow, ScanStruct or ScanAll to read the
+// returned rows.
+func (b DeleteBuilder) Returning(columns ...string) DeleteBuilder {
+	return builder.Extend(b, "Returning", columns).(DeleteBuilder)
 }
 
-func (d *deleteData) Query() (*sql.Rows, error) {
-	if d.RunWith == nil {
-		return nil, RunnerNotSet
-	}
-	return QueryWith(d.RunWith, d)
+// Suffix adds an expression to the end of the query
+func (b DeleteBuilder) Suffix(sql string, args ...interface{}) DeleteBuilder {
+	return builder.Append(b, "Suffixes", Expr(sql, args...)).(DeleteBuilder)
 }
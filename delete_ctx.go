@@ -10,14 +10,15 @@ import (
 )
 
 func (d *deleteData) ExecContext(ctx context.Context) (sql.Result, error) {
-	if d.RunWith == nil {
-		return nil, RunnerNotSet
-	}
-	ctxRunner, ok := d.RunWith.(ExecerContext)
-	if !ok {
-		return nil, NoContextSupport
-	}
-	return ExecContextWith(ctx, ctxRunner, d)
+	return execContextWithRunner(ctx, d.RunWith, d)
+}
+
+func (d *deleteData) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	return queryContextWithRunner(ctx, d.RunWith, d)
+}
+
+func (d *deleteData) QueryRowContext(ctx context.Context) RowScanner {
+	return queryRowContextWithRunner(ctx, d.RunWith, d)
 }
 
 // ExecContext builds and ExecContexts the query with the Runner set by RunWith.
@@ -25,3 +26,40 @@ func (b DeleteBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
 	data := builder.GetStruct(b).(deleteData)
 	return data.ExecContext(ctx)
 }
+
+// QueryContext builds and QueryContexts the query with the Runner set by RunWith.
+func (b DeleteBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	data := builder.GetStruct(b).(deleteData)
+	return data.QueryContext(ctx)
+}
+
+// QueryRowContext builds and QueryRowContexts the query with the Runner set by RunWith.
+func (b DeleteBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	data := builder.GetStruct(b).(deleteData)
+	return data.QueryRowContext(ctx)
+}
+
+// ScanContext is a shortcut for QueryRowContext().Scan.
+func (b DeleteBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return b.QueryRowContext(ctx).Scan(dest...)
+}
+
+// ScanStructContext is like ScanStruct, using QueryContext.
+func (b DeleteBuilder) ScanStructContext(ctx context.Context, dest interface{}) error {
+	data := builder.GetStruct(b).(deleteData)
+	rows, err := data.QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	return scanOne(rows, dest, false)
+}
+
+// ScanAllContext is like ScanAll, using QueryContext.
+func (b DeleteBuilder) ScanAllContext(ctx context.Context, dest interface{}) error {
+	data := builder.GetStruct(b).(deleteData)
+	rows, err := data.QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	return scanAll(rows, dest, false)
+}
@@ -16,6 +16,15 @@ func TestDeleteBuilderContextRunners(t *testing.T) {
 
 	b.ExecContext(ctx)
 	assert.Equal(t, expectedSql, db.LastExecSql)
+
+	b.QueryContext(ctx)
+	assert.Equal(t, expectedSql, db.LastQuerySql)
+
+	b.QueryRowContext(ctx)
+	assert.Equal(t, expectedSql, db.LastQueryRowSql)
+
+	err := b.ScanContext(ctx)
+	assert.NoError(t, err)
 }
 
 func TestDeleteBuilderContextNoRunner(t *testing.T) {
@@ -23,4 +32,10 @@ func TestDeleteBuilderContextNoRunner(t *testing.T) {
 
 	_, err := b.ExecContext(ctx)
 	assert.Equal(t, RunnerNotSet, err)
+
+	_, err = b.QueryContext(ctx)
+	assert.Equal(t, RunnerNotSet, err)
+
+	err = b.ScanContext(ctx)
+	assert.Equal(t, RunnerNotSet, err)
 }
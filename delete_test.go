@@ -1,6 +1,7 @@
 package squirrel
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -52,6 +53,15 @@ func TestDeleteBuilderRunners(t *testing.T) {
 
 	b.Exec()
 	assert.Equal(t, expectedSql, db.LastExecSql)
+
+	b.Query()
+	assert.Equal(t, expectedSql, db.LastQuerySql)
+
+	b.QueryRow()
+	assert.Equal(t, expectedSql, db.LastQueryRowSql)
+
+	err := b.Scan()
+	assert.NoError(t, err)
 }
 
 func TestDeleteBuilderNoRunner(t *testing.T) {
@@ -59,6 +69,12 @@ func TestDeleteBuilderNoRunner(t *testing.T) {
 
 	_, err := b.Exec()
 	assert.Equal(t, RunnerNotSet, err)
+
+	_, err = b.Query()
+	assert.Equal(t, RunnerNotSet, err)
+
+	err = b.Scan()
+	assert.Equal(t, RunnerNotSet, err)
 }
 
 func TestDeleteWithQuery(t *testing.T) {
@@ -70,3 +86,99 @@ func TestDeleteWithQuery(t *testing.T) {
 
 	assert.Equal(t, expectedSql, db.LastQuerySql)
 }
+
+func TestDeleteBuilderUsing(t *testing.T) {
+	inactive := Select("id").From("customers").Where("last_seen < ?", "2020-01-01")
+
+	sql, args, err := Delete("orders").
+		Prefix("WITH recent AS (SELECT ?::date AS day)", "2021-01-01").
+		Using("items i").
+		UsingSelect(inactive, "c").
+		Join("products p ON p.id = i.product_id").
+		Where("orders.id = i.order_id AND orders.customer_id = c.id").
+		Where("p.discontinued = ?", true).
+		Suffix("RETURNING orders.id, ? AS batch", 7).
+		Dialect(PostgreSQL).
+		ToSql()
+	assert.NoError(t, err)
+	expectedSql := "WITH recent AS (SELECT $1::date AS day) DELETE FROM orders " +
+		"USING items i, (SELECT id FROM customers WHERE last_seen < $2) AS c " +
+		"JOIN products p ON p.id = i.product_id " +
+		"WHERE orders.id = i.order_id AND orders.customer_id = c.id AND p.discontinued = $3 " +
+		"RETURNING orders.id, $4 AS batch"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"2021-01-01", "2020-01-01", true, 7}, args)
+}
+
+func TestDeleteBuilderJoin(t *testing.T) {
+	totals := Select("order_id", "SUM(amount) AS total").From("items").GroupBy("order_id")
+
+	sql, args, err := Delete("orders o").
+		JoinSelect(totals, "t", "t.order_id = o.id AND t.total < ?", 10).
+		Where("o.status = ?", "open").
+		Dialect(MySQL).
+		ToSql()
+	assert.NoError(t, err)
+	expectedSql := "DELETE o FROM orders o " +
+		"JOIN (SELECT order_id, SUM(amount) AS total FROM items GROUP BY order_id) AS t " +
+		"ON t.order_id = o.id AND t.total < ? WHERE o.status = ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{10, "open"}, args)
+
+	sql, _, err = Delete("orders").
+		Targets("orders", "items").
+		LeftJoin("items ON items.order_id = orders.id").
+		Where("orders.id = ?", 1).
+		Dialect(MySQL).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE orders, items FROM orders LEFT JOIN items ON items.order_id = orders.id WHERE orders.id = ?", sql)
+
+	sql, _, err = Delete("orders AS o").
+		Join("customers c ON c.id = o.customer_id").
+		Where("c.banned = ?", true).
+		Returning("id").
+		Dialect(SQLServer).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE o OUTPUT DELETED.id FROM orders AS o JOIN customers c ON c.id = o.customer_id WHERE c.banned = @p1", sql)
+}
+
+func TestDeleteBuilderTablesErrors(t *testing.T) {
+	_, _, err := Delete("a").Join("b USING (id)").Dialect(PostgreSQL).ToSql()
+	assert.EqualError(t, err, "postgres dialect does not support DELETE ... JOIN; join the tables of Using instead")
+
+	_, _, err = Delete("a").Targets("a").Dialect(PostgreSQL).ToSql()
+	assert.EqualError(t, err, "postgres dialect does not support deleting from several tables")
+
+	_, _, err = Delete("a").Using("b").Dialect(MySQL).ToSql()
+	assert.EqualError(t, err, "mysql dialect does not support DELETE ... USING; use Join instead")
+
+	_, _, err = Delete("a").Using("b").Dialect(SQLite).ToSql()
+	assert.EqualError(t, err, "sqlite3 dialect does not support deletes referencing other tables")
+
+	_, _, err = Delete("a").Using("b").Targets("a").ToSql()
+	assert.EqualError(t, err, "delete statements cannot have both Targets and Using")
+}
+
+func TestDeleteBuilderUnfiltered(t *testing.T) {
+	b := Delete("t")
+
+	_, _, err := b.ToSql()
+	assert.EqualError(t, err, "delete from t: statement would affect every row of the table; call AllRows to allow it")
+	assert.True(t, errors.Is(err, UnfilteredMutation))
+
+	_, _, err = b.Where(Eq{}).Dialect(SQLite).ToSql()
+	assert.True(t, errors.Is(err, UnfilteredMutation))
+
+	_, _, err = b.Where(map[string]interface{}{}).Limit(10).ToSql()
+	assert.True(t, errors.Is(err, UnfilteredMutation))
+
+	sql, _, err := b.AllRows().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t", sql)
+
+	sql, _, err = StatementBuilder.Unsafe().Delete("t").Where(And{}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE (1=1)", sql)
+}
@@ -0,0 +1,523 @@
+package squirrel
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// Dialect describes the SQL syntax of a database engine.
+//
+// A Dialect set on a builder (or on StatementBuilder) selects its
+// PlaceholderFormat and controls how the builder renders pagination, boolean
+// literals and upserts. Custom dialects can be built by embedding one of the
+// built-in dialects and overriding its exported methods.
+type Dialect interface {
+	// Name returns the name of the database engine, e.g. "postgres".
+	Name() string
+
+	// PlaceholderFormat returns the PlaceholderFormat expected by the engine.
+	PlaceholderFormat() PlaceholderFormat
+
+	// QuoteIdent quotes an identifier, quoting each part of a
+	// schema-qualified name separately.
+	QuoteIdent(ident string) string
+
+	// BoolLiteral returns the SQL for an always true or always false
+	// predicate, as used by e.g. an empty Eq or Or.
+	BoolLiteral(b bool) string
+
+	// SupportsReturning reports whether INSERT, UPDATE and DELETE statements
+	// can have a RETURNING clause.
+	SupportsReturning() bool
+
+	pagination() paginationStyle
+	upsertFormat() UpsertFormat
+	tableAlias(alias string) string
+	supportsRowValues() bool
+	locking() lockingStyle
+	supportsOutput() bool
+	maxParams() int
+	maxValuesRows() int
+	multiTable() multiTableStyle
+	multiTableDelete() multiTableStyle
+	supportsNullsOrder() bool
+	literals() literalStyle
+}
+
+// paginationStyle is the way a Dialect limits the rows of a SELECT statement.
+type paginationStyle int
+
+const (
+	// LIMIT n OFFSET m
+	paginationLimitOffset paginationStyle = iota
+	// OFFSET m ROWS FETCH NEXT n ROWS ONLY
+	paginationOffsetFetch
+	// SELECT TOP (n) ..., or OFFSET ... FETCH when an offset is set
+	paginationTop
+	// SELECT * FROM (SELECT ..., ROW_NUMBER() OVER (ORDER BY ...) AS rnum ...)
+	// WHERE rnum ...
+	paginationRowNumber
+)
+
+// lockingStyle is the way a Dialect locks the rows selected by a query.
+type lockingStyle int
+
+const (
+	// FOR UPDATE|SHARE [OF ...] [NOWAIT|SKIP LOCKED]
+	lockingFor lockingStyle = iota
+	// as lockingFor, with LOCK IN SHARE MODE for a plain FOR SHARE
+	lockingMySQL
+	// FOR UPDATE only
+	lockingUpdateOnly
+	// no locking clause
+	lockingNone
+)
+
+// multiTableStyle is the way a Dialect references other tables than the one
+// an UPDATE or DELETE statement modifies.
+type multiTableStyle int
+
+const (
+	// any of the styles below, as the query is written
+	multiTableAny multiTableStyle = iota
+	// UPDATE t SET ... FROM u [JOIN v ON ...]
+	// DELETE FROM t USING u [JOIN v ON ...]
+	multiTableFrom
+	// UPDATE t JOIN u ON ... SET ...
+	// DELETE t FROM t JOIN u ON ...
+	multiTableJoin
+	// no other tables
+	multiTableNone
+)
+
+// literalStyle is the way a Dialect writes values as literals.
+type literalStyle int
+
+const (
+	// 'it''s', X'0a0b', TRUE, '2006-01-02 15:04:05.999999999-07:00'
+	literalsStandard literalStyle = iota
+	// as literalsStandard, with '\x0a0b'::bytea
+	literalsPostgres
+	// as literalsStandard, with backslash escapes and times in UTC without
+	// an offset
+	literalsMySQL
+	// as literalsStandard, with 1 and 0 for booleans
+	literalsSQLite
+	// HEXTORAW('0a0b'), 1, TIMESTAMP '2006-01-02 15:04:05.999999999 -07:00'
+	literalsOracle
+	// N'it''s', 0x0a0b, 1, '2006-01-02T15:04:05.9999999-07:00'
+	literalsSQLServer
+)
+
+var (
+	// PostgreSQL is a Dialect for PostgreSQL.
+	PostgreSQL Dialect = &dialect{
+		name:        "postgres",
+		placeholder: Dollar,
+		quote:       [2]string{`"`, `"`},
+		bools:       [2]string{"FALSE", "TRUE"},
+		paginate:    paginationLimitOffset,
+		returning:   true,
+		upsert:      OnConflictUpsert,
+		rowValues:   true,
+		params:      65535,
+		tables:      multiTableFrom,
+		deletes:     multiTableFrom,
+		literal:     literalsPostgres,
+	}
+
+	// MySQL is a Dialect for MySQL and MariaDB.
+	MySQL Dialect = &dialect{
+		name:        "mysql",
+		placeholder: Question,
+		quote:       [2]string{"`", "`"},
+		bools:       [2]string{"FALSE", "TRUE"},
+		paginate:    paginationLimitOffset,
+		upsert:      OnDuplicateKeyUpsert,
+		rowValues:   true,
+		lock:        lockingMySQL,
+		params:      65535,
+		tables:      multiTableJoin,
+		deletes:     multiTableJoin,
+		noNulls:     true,
+		literal:     literalsMySQL,
+	}
+
+	// SQLite is a Dialect for SQLite. Its bind parameter limit is the 999 of
+	// SQLite releases before 3.32, which raised it to 32766.
+	SQLite Dialect = &dialect{
+		name:        "sqlite3",
+		placeholder: Question,
+		quote:       [2]string{`"`, `"`},
+		bools:       [2]string{"0", "1"},
+		paginate:    paginationLimitOffset,
+		returning:   true,
+		upsert:      OnConflictUpsert,
+		rowValues:   true,
+		lock:        lockingNone,
+		params:      999,
+		tables:      multiTableFrom,
+		deletes:     multiTableNone,
+		literal:     literalsSQLite,
+	}
+
+	// Oracle is a Dialect for Oracle Database 12c and later. It has no
+	// multi-row VALUES lists, so its inserts have a single row of values.
+	Oracle Dialect = &dialect{
+		name:        "oracle",
+		placeholder: Colon,
+		quote:       [2]string{`"`, `"`},
+		bools:       [2]string{sqlFalse, sqlTrue},
+		paginate:    paginationOffsetFetch,
+		noTableAs:   true,
+		lock:        lockingUpdateOnly,
+		params:      65535,
+		rows:        1,
+		tables:      multiTableNone,
+		deletes:     multiTableNone,
+		literal:     literalsOracle,
+	}
+
+	// Oracle11g is a Dialect for Oracle Database releases before 12c, which
+	// lack OFFSET and FETCH. Queries are paginated by numbering their rows
+	// with ROW_NUMBER() in an rnum column instead.
+	Oracle11g Dialect = &dialect{
+		name:        "oracle",
+		placeholder: Colon,
+		quote:       [2]string{`"`, `"`},
+		bools:       [2]string{sqlFalse, sqlTrue},
+		paginate:    paginationRowNumber,
+		noTableAs:   true,
+		lock:        lockingUpdateOnly,
+		params:      65535,
+		rows:        1,
+		tables:      multiTableNone,
+		deletes:     multiTableNone,
+		literal:     literalsOracle,
+	}
+
+	// SQLServer is a Dialect for Microsoft SQL Server.
+	SQLServer Dialect = &dialect{
+		name:        "sqlserver",
+		placeholder: AtP,
+		quote:       [2]string{"[", "]"},
+		bools:       [2]string{sqlFalse, sqlTrue},
+		paginate:    paginationTop,
+		lock:        lockingNone,
+		output:      true,
+		params:      2100,
+		rows:        1000,
+		tables:      multiTableFrom,
+		deletes:     multiTableJoin,
+		noNulls:     true,
+		literal:     literalsSQLServer,
+	}
+
+	// defaultDialect is used by builders without a Dialect and renders the
+	// portable syntax squirrel has always produced.
+	defaultDialect Dialect = &dialect{
+		name:        "default",
+		placeholder: Question,
+		quote:       [2]string{`"`, `"`},
+		bools:       [2]string{sqlFalse, sqlTrue},
+		paginate:    paginationLimitOffset,
+		returning:   true,
+		upsert:      OnConflictUpsert,
+	}
+)
+
+type dialect struct {
+	name        string
+	placeholder PlaceholderFormat
+	quote       [2]string
+	bools       [2]string
+	paginate    paginationStyle
+	returning   bool
+	upsert      UpsertFormat
+	noTableAs   bool
+	rowValues   bool
+	lock        lockingStyle
+	output      bool
+	params      int
+	rows        int
+	tables      multiTableStyle
+	deletes     multiTableStyle
+	noNulls     bool
+	literal     literalStyle
+}
+
+func (d *dialect) Name() string {
+	return d.name
+}
+
+func (d *dialect) PlaceholderFormat() PlaceholderFormat {
+	return d.placeholder
+}
+
+func (d *dialect) QuoteIdent(ident string) string {
+	parts := strings.Split(ident, ".")
+	for i, p := range parts {
+		if p == "*" {
+			continue
+		}
+		p = strings.Replace(p, d.quote[1], d.quote[1]+d.quote[1], -1)
+		parts[i] = d.quote[0] + p + d.quote[1]
+	}
+	return strings.Join(parts, ".")
+}
+
+func (d *dialect) BoolLiteral(b bool) string {
+	if b {
+		return d.bools[1]
+	}
+	return d.bools[0]
+}
+
+func (d *dialect) SupportsReturning() bool {
+	return d.returning
+}
+
+func (d *dialect) pagination() paginationStyle {
+	return d.paginate
+}
+
+func (d *dialect) upsertFormat() UpsertFormat {
+	return d.upsert
+}
+
+// tableAlias returns the clause aliasing a table or derived table; Oracle
+// rejects the AS keyword there.
+func (d *dialect) tableAlias(alias string) string {
+	if d.noTableAs {
+		return " " + alias
+	}
+	return " AS " + alias
+}
+
+// supportsRowValues reports whether row values can be compared, as in
+// (a, b) > (1, 2).
+func (d *dialect) supportsRowValues() bool {
+	return d.rowValues
+}
+
+func (d *dialect) locking() lockingStyle {
+	return d.lock
+}
+
+// supportsOutput reports whether INSERT, UPDATE and DELETE statements can have
+// an OUTPUT clause, SQL Server's RETURNING.
+func (d *dialect) supportsOutput() bool {
+	return d.output
+}
+
+// maxParams returns the maximum number of bind parameters of a statement, or
+// 0 if it is not known.
+func (d *dialect) maxParams() int {
+	return d.params
+}
+
+// maxValuesRows returns the maximum number of rows of the VALUES list of an
+// INSERT statement, or 0 if it is not known.
+func (d *dialect) maxValuesRows() int {
+	return d.rows
+}
+
+// multiTable returns the way UPDATE statements reference other tables.
+func (d *dialect) multiTable() multiTableStyle {
+	return d.tables
+}
+
+// multiTableDelete returns the way DELETE statements reference other tables.
+func (d *dialect) multiTableDelete() multiTableStyle {
+	return d.deletes
+}
+
+// supportsNullsOrder reports whether ORDER BY clauses can have NULLS FIRST and
+// NULLS LAST options.
+func (d *dialect) supportsNullsOrder() bool {
+	return !d.noNulls
+}
+
+func (d *dialect) literals() literalStyle {
+	return d.literal
+}
+
+func setDialect(b interface{}, d Dialect) interface{} {
+	b = builder.Set(b, "Dialect", d)
+	if d != nil {
+		b = builder.Set(b, "PlaceholderFormat", d.PlaceholderFormat())
+	}
+	return b
+}
+
+// dialectOr returns d, or defaultDialect if d is nil.
+func dialectOr(d Dialect) Dialect {
+	if d == nil {
+		return defaultDialect
+	}
+	return d
+}
+
+// dialectSqlizer is implemented by Sqlizers whose SQL depends on the Dialect
+// of the statement they are part of, e.g. Eq with its boolean literals.
+type dialectSqlizer interface {
+	toSqlDialect(d Dialect) (string, []interface{}, error)
+}
+
+// toSqlDialect returns the SQL of s as rendered for d.
+func toSqlDialect(d Dialect, s Sqlizer) (string, []interface{}, error) {
+	if ds, ok := s.(dialectSqlizer); ok {
+		return ds.toSqlDialect(d)
+	}
+	return s.ToSql()
+}
+
+// appendPaginationToSql writes the trailing LIMIT/OFFSET clause of a query to
+// w in the given style. TOP and ROWNUM pagination do not use a trailing clause
+// when only a limit is set or at all, respectively; callers handle those.
+func appendPaginationToSql(w io.Writer, style paginationStyle, limit, offset string) error {
+	var err error
+	switch style {
+	case paginationLimitOffset:
+		if len(limit) > 0 {
+			_, err = fmt.Fprintf(w, " LIMIT %s", limit)
+		}
+		if err == nil && len(offset) > 0 {
+			_, err = fmt.Fprintf(w, " OFFSET %s", offset)
+		}
+	case paginationOffsetFetch, paginationTop:
+		if len(offset) > 0 {
+			_, err = fmt.Fprintf(w, " OFFSET %s ROWS", offset)
+			if err == nil && len(limit) > 0 {
+				_, err = fmt.Fprintf(w, " FETCH NEXT %s ROWS ONLY", limit)
+			}
+		} else if len(limit) > 0 {
+			if style == paginationTop {
+				// SQL Server only accepts FETCH after OFFSET.
+				_, err = fmt.Fprintf(w, " OFFSET 0 ROWS FETCH NEXT %s ROWS ONLY", limit)
+			} else {
+				_, err = fmt.Fprintf(w, " FETCH FIRST %s ROWS ONLY", limit)
+			}
+		}
+	}
+	return err
+}
+
+// mutationTop validates the LIMIT and OFFSET of an UPDATE or DELETE statement
+// for d and returns the TOP clause the statement needs, if any.
+func mutationTop(d Dialect, limit, offset string) (string, error) {
+	switch d.pagination() {
+	case paginationLimitOffset:
+		return "", nil
+	case paginationTop:
+		if len(offset) > 0 {
+			return "", fmt.Errorf("%s dialect does not support OFFSET in UPDATE and DELETE statements", d.Name())
+		}
+		if len(limit) > 0 {
+			return fmt.Sprintf("TOP (%s) ", limit), nil
+		}
+		return "", nil
+	default:
+		if len(limit) > 0 || len(offset) > 0 {
+			return "", fmt.Errorf("%s dialect does not support LIMIT or OFFSET in UPDATE and DELETE statements", d.Name())
+		}
+		return "", nil
+	}
+}
+
+// rowNumberColumn returns the rnum column numbering the rows of a query
+// ordered by orderBy.
+func rowNumberColumn(orderBy string) string {
+	if len(orderBy) == 0 {
+		orderBy = "NULL"
+	}
+	return fmt.Sprintf("ROW_NUMBER() OVER (ORDER BY %s) AS rnum", orderBy)
+}
+
+// filterOwnRowNumber selects the rows of the query sql, numbered by an rnum
+// column of its own, that are within limit and offset, as LimitRowNum did
+// before ROW_NUMBER() was added for it.
+func filterOwnRowNumber(sql, limit, offset string) (string, error) {
+	var lim, off uint64
+	var err error
+	if len(limit) > 0 {
+		if lim, err = strconv.ParseUint(limit, 10, 64); err != nil {
+			return "", fmt.Errorf("invalid limit %q: %v", limit, err)
+		}
+	}
+	if len(offset) > 0 {
+		if off, err = strconv.ParseUint(offset, 10, 64); err != nil {
+			return "", fmt.Errorf("invalid offset %q: %v", offset, err)
+		}
+	}
+
+	filter := fmt.Sprintf("SELECT * FROM (%s) WHERE rnum >= %d", sql, off+1)
+	if len(limit) > 0 {
+		filter += fmt.Sprintf(" AND rnum < %d", off+lim+1)
+	}
+	return filter, nil
+}
+
+// projectsRowNumber reports whether columns, the SQL of the result columns of
+// a query, has a column named or aliased rnum.
+func projectsRowNumber(columns string) bool {
+	depth, start := 0, 0
+	for i := 0; i <= len(columns); i++ {
+		if i < len(columns) {
+			switch columns[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		column := strings.TrimSpace(columns[start:i])
+		start = i + 1
+		name := strings.Trim(column[strings.LastIndexAny(column, " \t\n.")+1:], `"`)
+		if strings.EqualFold(name, "rnum") {
+			return true
+		}
+	}
+	return false
+}
+
+// filterRowNumber selects the rows of the query sql, numbered by its
+// rowNumberColumn, that are within limit and offset.
+func filterRowNumber(sql, limit, offset string) (string, error) {
+	var lim, off uint64
+	var err error
+	if len(limit) > 0 {
+		if lim, err = strconv.ParseUint(limit, 10, 64); err != nil {
+			return "", fmt.Errorf("invalid limit %q: %v", limit, err)
+		}
+	}
+	if len(offset) > 0 {
+		if off, err = strconv.ParseUint(offset, 10, 64); err != nil {
+			return "", fmt.Errorf("invalid offset %q: %v", offset, err)
+		}
+	}
+
+	var conds []string
+	if off > 0 {
+		conds = append(conds, fmt.Sprintf("rnum > %d", off))
+	}
+	if len(limit) > 0 {
+		conds = append(conds, fmt.Sprintf("rnum <= %d", off+lim))
+	}
+	if len(conds) == 0 {
+		return fmt.Sprintf("SELECT * FROM (%s) ORDER BY rnum", sql), nil
+	}
+	return fmt.Sprintf("SELECT * FROM (%s) WHERE %s ORDER BY rnum", sql, strings.Join(conds, " AND ")), nil
+}
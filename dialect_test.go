@@ -0,0 +1,237 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialectPlaceholderFormat(t *testing.T) {
+	sb := StatementBuilder.Dialect(PostgreSQL)
+
+	sql, _, err := sb.Select("a").From("t").Where("b = ? AND c = ?", 1, 2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = $1 AND c = $2", sql)
+
+	sql, _, err = sb.Update("t").Set("a", 1).Where("b = ?", 2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = $1 WHERE b = $2", sql)
+
+	sql, _, err = sb.Dialect(Oracle).Delete("t").Where("b = ?", 2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE b = :1", sql)
+
+	ms := sb.Dialect(SQLServer).Select("a").From("t").Where("b = ? AND c IN (?,?)", 1, 2, 3)
+	sql, args, err := ms.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = @p1 AND c IN (@p2,@p3)", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	sql, err = Interpolate(SQLServer, ms)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = 1 AND c IN (2,3)", sql)
+
+	// An explicit PlaceholderFormat set afterwards wins.
+	sql, _, err = sb.Select("a").From("t").Where("b = ?", 1).PlaceholderFormat(Question).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = ?", sql)
+}
+
+func TestDialectQuoteIdent(t *testing.T) {
+	assert.Equal(t, `"users"`, PostgreSQL.QuoteIdent("users"))
+	assert.Equal(t, `"public"."users"`, PostgreSQL.QuoteIdent("public.users"))
+	assert.Equal(t, `"we""ird"`, SQLite.QuoteIdent(`we"ird`))
+	assert.Equal(t, "`db`.`order`", MySQL.QuoteIdent("db.order"))
+	assert.Equal(t, "`u`.*", MySQL.QuoteIdent("u.*"))
+	assert.Equal(t, "[dbo].[user]", SQLServer.QuoteIdent("dbo.user"))
+	assert.Equal(t, "[a]]b]", SQLServer.QuoteIdent("a]b"))
+	assert.Equal(t, `"ORDERS"`, Oracle.QuoteIdent("ORDERS"))
+}
+
+func TestDialectBoolLiterals(t *testing.T) {
+	b := Select("a").From("t").
+		Where(Eq{}).
+		Where(Or{}).
+		Where(And{Eq{"b": []int{}}, NotEq{"c": []int{}}}).
+		Column(Alias(Eq{}, "always"))
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT a, ((1=1)) AS always FROM t WHERE (1=1) AND (1=0) AND ((1=0) AND (1=1))",
+		sql)
+
+	sql, _, err = b.Dialect(PostgreSQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT a, (TRUE) AS always FROM t WHERE TRUE AND FALSE AND (FALSE AND TRUE)",
+		sql)
+
+	sql, _, err = Update("t").Set("a", 1).Where(Eq{}).AllRows().Dialect(SQLite).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ? WHERE 1", sql)
+
+	sql, _, err = Delete("t").Where(Or{}).Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE (1=0)", sql)
+}
+
+func TestDialectSelectPagination(t *testing.T) {
+	b := Select("a").From("t").OrderBy("a").Limit(10).Offset(20)
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{PostgreSQL, "SELECT a FROM t ORDER BY a LIMIT 10 OFFSET 20"},
+		{MySQL, "SELECT a FROM t ORDER BY a LIMIT 10 OFFSET 20"},
+		{SQLite, "SELECT a FROM t ORDER BY a LIMIT 10 OFFSET 20"},
+		{SQLServer, "SELECT a FROM t ORDER BY a OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{Oracle, "SELECT a FROM t ORDER BY a OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{Oracle11g, "SELECT * FROM (SELECT a, ROW_NUMBER() OVER (ORDER BY a) AS rnum FROM t) WHERE rnum > 20 AND rnum <= 30 ORDER BY rnum"},
+	}
+	for _, test := range tests {
+		sql, _, err := b.Dialect(test.dialect).ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, sql, test.dialect.Name())
+	}
+}
+
+func TestDialectSelectPaginationLimitOnly(t *testing.T) {
+	b := Select("a").Distinct().From("t").Limit(5)
+
+	sql, _, err := b.Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT TOP (5) a FROM t", sql)
+
+	sql, _, err = b.Dialect(Oracle).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT a FROM t FETCH FIRST 5 ROWS ONLY", sql)
+
+	sql, _, err = Select("a").From("t").Offset(5).Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t ORDER BY (SELECT NULL) OFFSET 5 ROWS", sql)
+
+	sql, _, err = Select("a").From("t").Offset(5).Dialect(Oracle).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t OFFSET 5 ROWS", sql)
+
+	sql, _, err = Select("a").From("t").Offset(5).Dialect(Oracle11g).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (SELECT a, ROW_NUMBER() OVER (ORDER BY NULL) AS rnum FROM t) WHERE rnum > 5 ORDER BY rnum", sql)
+}
+
+func TestDialectRowNumberHead(t *testing.T) {
+	b := Select("a").From("r").
+		Prefix("/* report */").
+		With("r", Select("a").From("t").Where("b = ?", "W")).
+		Where("a > ?", "A").
+		OrderByClause("a = ? DESC", "O").
+		Offset(10).
+		Dialect(Oracle11g)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"/* report */ WITH r AS (SELECT a FROM t WHERE b = :1) "+
+			"SELECT * FROM (SELECT a, ROW_NUMBER() OVER (ORDER BY a = :2 DESC) AS rnum FROM r WHERE a > :3) "+
+			"WHERE rnum > 10 ORDER BY rnum",
+		sql)
+	assert.Equal(t, []interface{}{"W", "O", "A"}, args)
+
+	sql, args, err = b.Distinct().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"/* report */ WITH r AS (SELECT a FROM t WHERE b = :1) "+
+			"SELECT * FROM (SELECT q.*, ROW_NUMBER() OVER (ORDER BY a = :2 DESC) AS rnum "+
+			"FROM (SELECT DISTINCT a FROM r WHERE a > :3) q) WHERE rnum > 10 ORDER BY rnum",
+		sql)
+	assert.Equal(t, []interface{}{"W", "O", "A"}, args)
+
+	sql, args, err = Union(Select("a").From("t"), Select("a").From("u")).
+		Prefix("WITH p AS (SELECT ? AS v FROM dual)", "P").
+		OrderByClause("a = ? DESC", "O").
+		Limit(5).
+		Dialect(Oracle11g).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"WITH p AS (SELECT :1 AS v FROM dual) SELECT * FROM (SELECT q.*, ROW_NUMBER() OVER (ORDER BY a = :2 DESC) AS rnum "+
+			"FROM (SELECT a FROM t UNION SELECT a FROM u) q) WHERE rnum <= 5 ORDER BY rnum",
+		sql)
+	assert.Equal(t, []interface{}{"P", "O"}, args)
+}
+
+func TestDialectCompoundPagination(t *testing.T) {
+	b := Union(Select("a").From("t"), Select("a").From("u")).Limit(5)
+
+	sql, _, err := b.Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t UNION SELECT a FROM u ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY", sql)
+
+	sql, _, err = b.Dialect(Oracle).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t UNION SELECT a FROM u FETCH FIRST 5 ROWS ONLY", sql)
+
+	sql, args, err := b.OrderByClause("a = ? DESC", 1).Offset(10).Dialect(Oracle11g).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT * FROM (SELECT q.*, ROW_NUMBER() OVER (ORDER BY a = :1 DESC) AS rnum "+
+			"FROM (SELECT a FROM t UNION SELECT a FROM u) q) WHERE rnum > 10 AND rnum <= 15 ORDER BY rnum",
+		sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestDialectCompoundRowNumberArgs(t *testing.T) {
+	b := Union(Select("a").From("t").Where("b = ?", "T"), Select("a").From("u").Where("b = ?", "U")).
+		OrderByClause("a = ? DESC", "O").
+		Limit(5)
+
+	sql, args, err := b.Dialect(Oracle11g).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT * FROM (SELECT q.*, ROW_NUMBER() OVER (ORDER BY a = :1 DESC) AS rnum "+
+			"FROM (SELECT a FROM t WHERE b = :2 UNION SELECT a FROM u WHERE b = :3) q) WHERE rnum <= 5 ORDER BY rnum",
+		sql)
+	assert.Equal(t, []interface{}{"O", "T", "U"}, args)
+}
+
+func TestDialectMutationLimit(t *testing.T) {
+	sql, _, err := Update("t").Set("a", 1).AllRows().Limit(5).Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE TOP (5) t SET a = @p1", sql)
+
+	sql, _, err = Delete("t").Where("a = ?", 1).Limit(5).Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE TOP (5) FROM t WHERE a = @p1", sql)
+
+	sql, _, err = Delete("t").AllRows().Limit(5).Dialect(MySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t LIMIT 5", sql)
+
+	_, _, err = Delete("t").Limit(5).Offset(1).Dialect(SQLServer).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Update("t").Set("a", 1).Limit(5).Dialect(Oracle).ToSql()
+	assert.Error(t, err)
+}
+
+func TestDialectUpsert(t *testing.T) {
+	b := Insert("t").Columns("id", "a").Values(1, 2).OnConflict("id").DoUpdateSetExcluded("a")
+
+	sql, _, err := b.Dialect(PostgreSQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (id,a) VALUES ($1,$2) ON CONFLICT (id) DO UPDATE SET a = EXCLUDED.a", sql)
+
+	sql, _, err = b.Dialect(MySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (id,a) VALUES (?,?) ON DUPLICATE KEY UPDATE a = VALUES(a)", sql)
+
+	// An explicit UpsertFormat wins over the Dialect.
+	sql, _, err = b.Dialect(MySQL).UpsertFormat(OnConflictUpsert).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (id,a) VALUES (?,?) ON CONFLICT (id) DO UPDATE SET a = EXCLUDED.a", sql)
+
+	_, _, err = b.Dialect(SQLServer).ToSql()
+	assert.Error(t, err)
+}
@@ -24,23 +24,48 @@ type expr struct {
 //
 // Ex:
 //     Expr("FROM_UNIXTIME(?)", t)
+//
+// Instead of ? placeholders, the fragment can have :name or @name parameters
+// bound by a single string-keyed map, a single struct whose db tags name its
+// fields as for InsertBuilder.SetStruct, or sql.NamedArg arguments only. The
+// same goes for the strings given with arguments to Where, Having, Prefix,
+// Suffix and the other clauses of the builders.
+//
+// Ex:
+//     Expr("status = :status AND (owner = :user OR assignee = :user)",
+//         map[string]interface{}{"status": "open", "user": 42})
+//     Expr("id IN (@ids)", sql.Named("ids", []int{1, 2, 3}))
+//
+// Each occurrence of a parameter is bound to its own placeholder, and slices
+// other than []byte are expanded to a placeholder per element. With a Dialect
+// whose PlaceholderFormat binds @name parameters, such as SQLServer, the
+// parameters bound by sql.NamedArg arguments other than slices are left as
+// @name and their arguments passed through instead.
 func Expr(sql string, args ...interface{}) expr {
 	return expr{sql: sql, args: args}
 }
 
 func (e expr) ToSql() (sql string, args []interface{}, err error) {
-	return e.sql, e.args, nil
+	return bindNamed(e.sql, e.args)
+}
+
+func (e expr) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	return bindNamedDialect(d, e.sql, e.args)
 }
 
 type concatExpr []interface{}
 
 func (ce concatExpr) ToSql() (sql string, args []interface{}, err error) {
+	return ce.toSqlDialect(nil)
+}
+
+func (ce concatExpr) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
 	for _, part := range ce {
 		switch p := part.(type) {
 		case string:
 			sql += p
 		case Sqlizer:
-			pSql, pArgs, err := p.ToSql()
+			pSql, pArgs, err := toSqlDialect(d, p)
 			if err != nil {
 				return "", nil, err
 			}
@@ -72,11 +97,15 @@ func (es exprs) AppendToSql(w io.Writer, sep string, args []interface{}) ([]inte
 				return nil, err
 			}
 		}
-		_, err := io.WriteString(w, e.sql)
+		sql, exprArgs, err := e.ToSql()
 		if err != nil {
 			return nil, err
 		}
-		args = append(args, e.args...)
+		_, err = io.WriteString(w, sql)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, exprArgs...)
 	}
 	return args, nil
 }
@@ -96,7 +125,11 @@ func Alias(expr Sqlizer, alias string) aliasExpr {
 }
 
 func (e aliasExpr) ToSql() (sql string, args []interface{}, err error) {
-	sql, args, err = e.expr.ToSql()
+	return e.toSqlDialect(nil)
+}
+
+func (e aliasExpr) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	sql, args, err = toSqlDialect(d, e.expr)
 	if err == nil {
 		if len(e.alias) > 0 {
 			sql = fmt.Sprintf("(%s) AS %s", sql, e.alias)
@@ -107,15 +140,34 @@ func (e aliasExpr) ToSql() (sql string, args []interface{}, err error) {
 	return
 }
 
+// derivedTable is a subquery used as a table in a FROM clause.
+type derivedTable struct {
+	query Sqlizer
+	alias string
+}
+
+func (t derivedTable) ToSql() (sql string, args []interface{}, err error) {
+	return t.toSqlDialect(nil)
+}
+
+func (t derivedTable) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	sql, args, err = nestedToSql(t.query)
+	if err == nil {
+		sql = fmt.Sprintf("(%s)%s", sql, dialectOr(d).tableAlias(t.alias))
+	}
+	return
+}
+
 // Eq is syntactic sugar for use with Where/Having/Set methods.
 // Ex:
 //     .Where(Eq{"id": 1})
 type Eq map[string]interface{}
 
-func (eq Eq) toSQL(useNotOpr bool) (sql string, args []interface{}, err error) {
+func (eq Eq) toSQL(d Dialect, useNotOpr bool) (sql string, args []interface{}, err error) {
+	d = dialectOr(d)
 	if len(eq) == 0 {
 		// Empty Sql{} evaluates to true.
-		sql = sqlTrue
+		sql = d.BoolLiteral(true)
 		return
 	}
 
@@ -124,14 +176,14 @@ func (eq Eq) toSQL(useNotOpr bool) (sql string, args []interface{}, err error) {
 		equalOpr    = "="
 		inOpr       = "IN"
 		nullOpr     = "IS"
-		inEmptyExpr = sqlFalse
+		inEmptyExpr = d.BoolLiteral(false)
 	)
 
 	if useNotOpr {
 		equalOpr = "<>"
 		inOpr = "NOT IN"
 		nullOpr = "IS NOT"
-		inEmptyExpr = sqlTrue
+		inEmptyExpr = d.BoolLiteral(true)
 	}
 
 	sortedKeys := getSortedKeys(eq)
@@ -183,7 +235,11 @@ func (eq Eq) toSQL(useNotOpr bool) (sql string, args []interface{}, err error) {
 }
 
 func (eq Eq) ToSql() (sql string, args []interface{}, err error) {
-	return eq.toSQL(false)
+	return eq.toSQL(nil, false)
+}
+
+func (eq Eq) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	return eq.toSQL(d, false)
 }
 
 // NotEq is syntactic sugar for use with Where/Having/Set methods.
@@ -192,7 +248,11 @@ func (eq Eq) ToSql() (sql string, args []interface{}, err error) {
 type NotEq Eq
 
 func (neq NotEq) ToSql() (sql string, args []interface{}, err error) {
-	return Eq(neq).toSQL(true)
+	return Eq(neq).toSQL(nil, true)
+}
+
+func (neq NotEq) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	return Eq(neq).toSQL(d, true)
 }
 
 // Like is syntactic sugar for use with LIKE conditions.
@@ -384,13 +444,13 @@ func (gtOrEq GtOrEq) ToSql() (sql string, args []interface{}, err error) {
 
 type conj []Sqlizer
 
-func (c conj) join(sep, defaultExpr string) (sql string, args []interface{}, err error) {
+func (c conj) join(d Dialect, sep string, empty bool) (sql string, args []interface{}, err error) {
 	if len(c) == 0 {
-		return defaultExpr, []interface{}{}, nil
+		return dialectOr(d).BoolLiteral(empty), []interface{}{}, nil
 	}
 	var sqlParts []string
 	for _, sqlizer := range c {
-		partSQL, partArgs, err := sqlizer.ToSql()
+		partSQL, partArgs, err := toSqlDialect(d, sqlizer)
 		if err != nil {
 			return "", nil, err
 		}
@@ -409,14 +469,22 @@ func (c conj) join(sep, defaultExpr string) (sql string, args []interface{}, err
 type And conj
 
 func (a And) ToSql() (string, []interface{}, error) {
-	return conj(a).join(" AND ", sqlTrue)
+	return a.toSqlDialect(nil)
+}
+
+func (a And) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	return conj(a).join(d, " AND ", true)
 }
 
 // Or conjunction Sqlizers
 type Or conj
 
 func (o Or) ToSql() (string, []interface{}, error) {
-	return conj(o).join(" OR ", sqlFalse)
+	return o.toSqlDialect(nil)
+}
+
+func (o Or) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	return conj(o).join(d, " OR ", false)
 }
 
 func getSortedKeys(exp map[string]interface{}) []string {
@@ -0,0 +1,217 @@
+package squirrel
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Ident is an identifier, such as a column or a schema-qualified table name,
+// quoted with the QuoteIdent method of the Dialect of the statement it is part
+// of, e.g.
+//   Select().Column(Ident("order")).From("t")
+// renders SELECT "order" FROM t, or SELECT `order` FROM t with MySQL.
+type Ident string
+
+func (i Ident) ToSql() (string, []interface{}, error) {
+	return i.toSqlDialect(nil)
+}
+
+func (i Ident) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	if len(i) == 0 {
+		return "", nil, errors.New("identifiers cannot be empty")
+	}
+	return dialectOr(d).QuoteIdent(string(i)), nil, nil
+}
+
+// InvalidIdentifier is wrapped by the errors returned by ToSql in strict mode
+// for identifiers that are neither quoted nor made of the characters
+// [A-Za-z0-9_.] only. See StatementBuilderType.StrictIdents.
+var InvalidIdentifier = errors.New("invalid identifier")
+
+// identKind is the kind of clause an identifier is checked for in strict mode.
+type identKind int
+
+const (
+	// name
+	identName identKind = iota
+	// name [[AS] alias], with name possibly * or ending with .*
+	identColumn
+	// name [[AS] alias]
+	identTable
+	// name [ASC|DESC] [NULLS FIRST|LAST]
+	identOrder
+)
+
+// identChecker checks identifiers for strict mode, accepting those quoted as
+// by the QuoteIdent method of its Dialect.
+type identChecker struct {
+	open, close string
+}
+
+func newIdentChecker(d Dialect) identChecker {
+	q := dialectOr(d).QuoteIdent("x")
+	i := strings.Index(q, "x")
+	return identChecker{open: q[:i], close: q[i+1:]}
+}
+
+// scan returns the length of the possibly schema-qualified name at the start
+// of s, or 0 if there is none.
+func (c identChecker) scan(s string, star bool) int {
+	i := 0
+	for {
+		switch {
+		case len(c.open) > 0 && strings.HasPrefix(s[i:], c.open):
+			j := i + len(c.open)
+			for {
+				k := strings.Index(s[j:], c.close)
+				if k < 0 {
+					return 0
+				}
+				j += k + len(c.close)
+				// Closing quotes are escaped by doubling them.
+				if !strings.HasPrefix(s[j:], c.close) {
+					break
+				}
+				j += len(c.close)
+			}
+			if j == i+len(c.open)+len(c.close) {
+				return 0
+			}
+			i = j
+		case star && strings.HasPrefix(s[i:], "*"):
+			return i + 1
+		default:
+			j := i
+			for j < len(s) && isIdentChar(s[j]) {
+				j++
+			}
+			if j == i {
+				return 0
+			}
+			i = j
+		}
+		if !strings.HasPrefix(s[i:], ".") {
+			return i
+		}
+		i++
+	}
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// check returns an error wrapping InvalidIdentifier if s is not a valid
+// identifier of the given kind.
+func (c identChecker) check(s string, kind identKind) error {
+	n := c.scan(s, kind == identColumn)
+	rest := s[n:]
+	if n > 0 && len(rest) > 0 && rest[0] == ' ' {
+		rest = strings.TrimLeft(rest, " ")
+		switch kind {
+		case identColumn, identTable:
+			if len(rest) > 3 && strings.EqualFold(rest[:3], "AS ") {
+				rest = strings.TrimLeft(rest[3:], " ")
+			}
+			if alias := c.scan(rest, false); alias > 0 {
+				rest = rest[alias:]
+			}
+		case identOrder:
+			words := strings.Fields(strings.ToUpper(rest))
+			if len(words) > 0 && (words[0] == "ASC" || words[0] == "DESC") {
+				words = words[1:]
+			}
+			if len(words) == 2 && words[0] == "NULLS" && (words[1] == "FIRST" || words[1] == "LAST") {
+				words = nil
+			}
+			rest = strings.Join(words, " ")
+		}
+	}
+	if n == 0 || len(rest) > 0 {
+		return fmt.Errorf("%w %q", InvalidIdentifier, s)
+	}
+	return nil
+}
+
+func (c identChecker) checkAll(idents []string, kind identKind) error {
+	for _, ident := range idents {
+		if err := c.check(ident, kind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkParts checks the parts of a clause given as strings, e.g. by
+// SelectBuilder.Columns, as identifiers of the given kind, and the
+// identifiers of the expressions among them.
+func (c identChecker) checkParts(parts []Sqlizer, kind identKind) error {
+	for _, p := range parts {
+		if p, ok := p.(*part); ok {
+			if s, ok := p.pred.(string); ok {
+				if err := c.check(s, kind); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := c.checkExpr(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkExpr checks the identifiers of the expression e: the keys of Eq, Lt,
+// Like and the other maps of expressions, and the columns of SortColumns
+// other than the expressions whitelisted by SortFields. The SQL of strings
+// and Exprs is not checked.
+func (c identChecker) checkExpr(e interface{}) error {
+	switch e := e.(type) {
+	case nil, string:
+		return nil
+	case *part:
+		return c.checkExpr(e.pred)
+	case *wherePart:
+		return c.checkExpr(e.pred)
+	case And:
+		return c.checkExprs(e)
+	case Or:
+		return c.checkExprs(e)
+	case SortColumn:
+		if e.trusted {
+			return nil
+		}
+		return c.check(e.Column, identName)
+	case keysetPredicate:
+		for _, column := range e.columns {
+			if err := c.checkExpr(column); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Eq, NotEq, Lt, Like and friends, or a map[string]interface{} given to
+	// Where.
+	v := reflect.ValueOf(e)
+	if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+		for _, key := range v.MapKeys() {
+			if err := c.check(key.String(), identName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c identChecker) checkExprs(exprs []Sqlizer) error {
+	for _, e := range exprs {
+		if err := c.checkExpr(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
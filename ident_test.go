@@ -0,0 +1,165 @@
+package squirrel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdent(t *testing.T) {
+	b := Select().Column(Ident("order")).Column(Ident("app.users.*")).From("t").Where(Eq{"a": 1})
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "order", "app"."users".* FROM t WHERE a = ?`, sql)
+
+	sql, _, err = b.Dialect(MySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `order`, `app`.`users`.* FROM t WHERE a = ?", sql)
+
+	sql, _, err = b.Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT [order], [app].[users].* FROM t WHERE a = @p1", sql)
+
+	_, _, err = Select().Column(Ident("")).ToSql()
+	assert.Error(t, err)
+}
+
+func TestIdentChecker(t *testing.T) {
+	c := newIdentChecker(PostgreSQL)
+	valid := []struct {
+		ident string
+		kind  identKind
+	}{
+		{"id", identName},
+		{"app.users", identName},
+		{`"weird name"."Column"`, identName},
+		{`"a""b"`, identName},
+		{"*", identColumn},
+		{"u.*", identColumn},
+		{"u.name AS n", identColumn},
+		{`name "Name"`, identColumn},
+		{"users u", identTable},
+		{"app.users AS u", identTable},
+		{"name", identOrder},
+		{"name desc", identOrder},
+		{"name ASC NULLS LAST", identOrder},
+		{"name NULLS FIRST", identOrder},
+	}
+	for _, v := range valid {
+		assert.NoError(t, c.check(v.ident, v.kind), v.ident)
+	}
+
+	invalid := []struct {
+		ident string
+		kind  identKind
+	}{
+		{"", identName},
+		{"a b", identName},
+		{"a-b", identName},
+		{"a;DROP TABLE t", identName},
+		{"lower(name)", identName},
+		{"a.", identName},
+		{`"unterminated`, identName},
+		{`""`, identName},
+		{"`a`", identName},
+		{"*", identName},
+		{"u.* x", identOrder},
+		{"COUNT(*)", identColumn},
+		{"a AS b c", identColumn},
+		{"users u, secrets", identTable},
+		{"name DESC, (SELECT 1)", identOrder},
+		{"name NULLS", identOrder},
+	}
+	for _, v := range invalid {
+		err := c.check(v.ident, v.kind)
+		assert.True(t, errors.Is(err, InvalidIdentifier), v.ident)
+	}
+
+	assert.NoError(t, newIdentChecker(MySQL).check("`a b`.c", identName))
+	assert.NoError(t, newIdentChecker(SQLServer).check("[a]]b]", identName))
+}
+
+func TestStrictIdents(t *testing.T) {
+	sb := StatementBuilder.StrictIdents()
+
+	sql, _, err := sb.Select("u.id", "u.name AS n").Column(Expr("COUNT(*)")).
+		From("users u").
+		Join("orders o ON o.user_id = u.id").
+		Where(Eq{"u.active": true}).
+		Where("o.total > ?", 10).
+		GroupBy("u.id", "u.name").
+		OrderBy("n DESC").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT u.id, u.name AS n, COUNT(*) FROM users u "+
+		"JOIN orders o ON o.user_id = u.id WHERE u.active = ? AND o.total > ? "+
+		"GROUP BY u.id, u.name ORDER BY n DESC", sql)
+
+	invalid := []Sqlizer{
+		sb.Select("COUNT(*)").From("t"),
+		sb.Select("a").From("t; DROP TABLE t"),
+		sb.Select("a").From("t").Where(Eq{"a = 1 OR 1": 1}),
+		sb.Select("a").From("t").Where(And{Or{Lt{"a": 1}, Like{"b c": "%"}}}),
+		sb.Select("a").From("t").Where(map[string]interface{}{"a)": 1}),
+		sb.Select("a").From("t").Having(Gt{"sum(a)": 1}),
+		sb.Select("a").From("t").GroupBy("a, b"),
+		sb.Select("a").From("t").OrderBy("a; --"),
+		sb.Select("a").From("t").Seek([]SortColumn{Asc("a b")}, 1),
+		sb.Union(sb.Select("a").From("t"), sb.Select("a").From("u")).OrderBy("1=1"),
+		sb.Insert("t").Columns("a b").Values(1),
+		sb.Insert("t(a)").Values(1),
+		sb.Insert("t").Columns("a").Values(1).OnConflict("a").DoUpdateSet("b=1", 1),
+		sb.Update("t").Set("a = a", 1).Where(Eq{"id": 1}),
+		sb.Update("t").Set("a", 1).Where(NotEq{"id)": 1}),
+		sb.Update("t").Set("a", 1).From("u, v").Where(Eq{"id": 1}),
+		sb.Delete("t").Where(GtOrEq{"x-y": 1}),
+		sb.Delete("t").Using("u v w").Where(Eq{"id": 1}),
+		sb.Delete("t").Where(Eq{"id": 1}).OrderBy("id LIMIT 1"),
+	}
+	for _, s := range invalid {
+		_, _, err := s.ToSql()
+		assert.True(t, errors.Is(err, InvalidIdentifier), "%v", err)
+	}
+
+	sql, _, err = sb.Update(`"order"`).Set(`"group"`, 1).Where(Eq{`"select"`: 1}).Dialect(PostgreSQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE "order" SET "group" = $1 WHERE "select" = $2`, sql)
+
+	_, _, err = sb.Update("`order`").Set("a", 1).Where(Eq{"id": 1}).Dialect(PostgreSQL).ToSql()
+	assert.True(t, errors.Is(err, InvalidIdentifier))
+
+	// Without strict mode, identifiers are interpolated as they are.
+	sql, _, err = Select("COUNT(*)").From("t").Where(Eq{"lower(a)": "x"}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM t WHERE lower(a) = ?", sql)
+}
+
+func TestStrictIdentsGeneratedColumns(t *testing.T) {
+	sb := StatementBuilder.StrictIdents()
+
+	sql, _, err := sb.Select("id", "name").From("users").Where(Eq{"active": true}).OrderBy("name").CountQuery().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM users WHERE active = ?", sql)
+
+	sql, _, err = sb.Select("name").Distinct().From("users").CountQuery().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT DISTINCT name FROM users) AS count_query", sql)
+
+	fields := SortFields{"name": "LOWER(u.name)", "id": ""}
+	columns, err := fields.Parse("-name,id")
+	assert.NoError(t, err)
+	sql, _, err = sb.Select("id").From("users u").OrderBySort(columns...).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users u ORDER BY LOWER(u.name) DESC, id ASC", sql)
+
+	sql, _, err = sb.Select("id").From("users u").Seek(columns, "moe", 42).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users u WHERE (LOWER(u.name) < ? OR (LOWER(u.name) = ? AND id > ?)) "+
+		"ORDER BY LOWER(u.name) DESC, id ASC", sql)
+
+	// Expressions that were not whitelisted are still refused.
+	_, _, err = sb.Select("id").From("users u").OrderBySort(Desc("LOWER(u.name)")).ToSql()
+	assert.True(t, errors.Is(err, InvalidIdentifier))
+}
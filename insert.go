@@ -14,6 +14,7 @@ import (
 
 type insertData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
 	RunWith           BaseRunner
 	Prefixes          exprs
 	StatementKeyword  string
@@ -23,34 +24,37 @@ type insertData struct {
 	Values            [][]interface{}
 	Suffixes          exprs
 	Select            *SelectBuilder
+	Returning         []string
+	StructErr         error
+	BatchRows         uint64
+	BatchTx           bool
+	statementOptions
+
+	UpsertFormat       UpsertFormat
+	ConflictColumns    []string
+	ConflictConstraint string
+	ConflictDoNothing  bool
+	ConflictSetClauses []setClause
+	ConflictWhereParts []Sqlizer
 }
 
 func (d *insertData) Exec() (sql.Result, error) {
-	if d.RunWith == nil {
-		return nil, RunnerNotSet
-	}
-	return ExecWith(d.RunWith, d)
+	return execWithRunner(d.RunWith, d)
 }
 
 func (d *insertData) Query() (*sql.Rows, error) {
-	if d.RunWith == nil {
-		return nil, RunnerNotSet
-	}
-	return QueryWith(d.RunWith, d)
+	return queryWithRunner(d.RunWith, d)
 }
 
 func (d *insertData) QueryRow() RowScanner {
-	if d.RunWith == nil {
-		return &Row{err: RunnerNotSet}
-	}
-	queryRower, ok := d.RunWith.(QueryRower)
-	if !ok {
-		return &Row{err: RunnerNotQueryRunner}
-	}
-	return QueryRowWith(queryRower, d)
+	return queryRowWithRunner(d.RunWith, d)
 }
 
 func (d *insertData) ToSql() (sqlStr string, args []interface{}, err error) {
+	if d.StructErr != nil {
+		err = d.StructErr
+		return
+	}
 	if len(d.Into) == 0 {
 		err = errors.New("insert statements must specify a table")
 		return
@@ -59,11 +63,24 @@ func (d *insertData) ToSql() (sqlStr string, args []interface{}, err error) {
 		err = errors.New("insert statements must have at least one set of values or select clause")
 		return
 	}
+	if d.StrictIdents {
+		if err = d.checkIdents(); err != nil {
+			return
+		}
+	}
+
+	returning, output, err := returningClauses(d.Dialect, d.Returning, outputInserted)
+	if err != nil {
+		return
+	}
 
 	sql := &bytes.Buffer{}
 
 	if len(d.Prefixes) > 0 {
-		args, _ = d.Prefixes.AppendToSql(sql, " ", args)
+		args, err = d.Prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
@@ -89,8 +106,15 @@ func (d *insertData) ToSql() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(") ")
 	}
 
+	if len(output) > 0 {
+		sql.WriteString(output)
+		sql.WriteString(" ")
+	}
+
 	if d.Select != nil {
 		args, err = d.appendSelectToSQL(sql, args)
+	} else if max := dialectOr(d.Dialect).maxValuesRows(); max > 0 && len(d.Values) > max {
+		err = fmt.Errorf("insert has %d rows of values, over the %s dialect limit of %d; use ExecBatch", len(d.Values), d.Dialect.Name(), max)
 	} else {
 		args, err = d.appendValuesToSQL(sql, args)
 	}
@@ -98,12 +122,38 @@ func (d *insertData) ToSql() (sqlStr string, args []interface{}, err error) {
 		return
 	}
 
+	if d.hasUpsert() {
+		upsertFormat := d.UpsertFormat
+		if upsertFormat == nil {
+			upsertFormat = dialectOr(d.Dialect).upsertFormat()
+		}
+		if upsertFormat == nil {
+			err = fmt.Errorf("%s dialect does not support upserts", d.Dialect.Name())
+			return
+		}
+		args, err = upsertFormat.appendUpsertToSql(sql, d, args)
+		if err != nil {
+			return
+		}
+	}
+
+	sql.WriteString(returning)
+
 	if len(d.Suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = d.Suffixes.AppendToSql(sql, " ", args)
+		args, err = d.Suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if d.CheckParams {
+		if err = d.checkParams(sql.String(), args); err != nil {
+			return
+		}
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr, args, err = finalizePlaceholders(d.PlaceholderFormat, sql.String(), args)
 	return
 }
 
@@ -120,8 +170,12 @@ func (d *insertData) appendValuesToSQL(w io.Writer, args []interface{}) ([]inter
 		for v, val := range row {
 			e, isExpr := val.(expr)
 			if isExpr {
-				valueStrings[v] = e.sql
-				args = append(args, e.args...)
+				exprSql, exprArgs, err := e.ToSql()
+				if err != nil {
+					return nil, err
+				}
+				valueStrings[v] = exprSql
+				args = append(args, exprArgs...)
 			} else {
 				valueStrings[v] = "?"
 				args = append(args, val)
@@ -151,6 +205,26 @@ func (d *insertData) appendSelectToSQL(w io.Writer, args []interface{}) ([]inter
 	return args, nil
 }
 
+// checkIdents checks the identifiers of the query for strict mode.
+func (d *insertData) checkIdents() error {
+	c := newIdentChecker(d.Dialect)
+	if err := c.check(d.Into, identTable); err != nil {
+		return err
+	}
+	if err := c.checkAll(d.Columns, identName); err != nil {
+		return err
+	}
+	if err := c.checkAll(d.ConflictColumns, identName); err != nil {
+		return err
+	}
+	for _, setClause := range d.ConflictSetClauses {
+		if err := c.check(setClause.column, identName); err != nil {
+			return err
+		}
+	}
+	return c.checkExprs(d.ConflictWhereParts)
+}
+
 // Builder
 
 // InsertBuilder builds SQL INSERT statements.
@@ -168,6 +242,25 @@ func (b InsertBuilder) PlaceholderFormat(f PlaceholderFormat) InsertBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(InsertBuilder)
 }
 
+func (b InsertBuilder) debugPlaceholder() string {
+	data := builder.GetStruct(b).(insertData)
+	return debugPlaceholder(data.PlaceholderFormat)
+}
+
+// Dialect sets the Dialect of the query, along with its PlaceholderFormat.
+//
+// Unless UpsertFormat is set, the Dialect also selects how the conflict clause
+// of the query is rendered.
+func (b InsertBuilder) Dialect(d Dialect) InsertBuilder {
+	return setDialect(b, d).(InsertBuilder)
+}
+
+// UpsertFormat sets UpsertFormat (e.g. OnConflictUpsert or
+// OnDuplicateKeyUpsert) used to render the conflict clause of the query.
+func (b InsertBuilder) UpsertFormat(f UpsertFormat) InsertBuilder {
+	return builder.Set(b, "UpsertFormat", f).(InsertBuilder)
+}
+
 // Runner methods
 
 // RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
@@ -198,6 +291,32 @@ func (b InsertBuilder) Scan(dest ...interface{}) error {
 	return b.QueryRow().Scan(dest...)
 }
 
+// ScanStruct builds and Querys the query with the Runner set by RunWith and
+// scans the first returned row into dest.
+//
+// See SelectBuilder.ScanStruct for the values dest can point to.
+func (b InsertBuilder) ScanStruct(dest interface{}) error {
+	data := builder.GetStruct(b).(insertData)
+	rows, err := data.Query()
+	if err != nil {
+		return err
+	}
+	return scanOne(rows, dest, false)
+}
+
+// ScanAll builds and Querys the query with the Runner set by RunWith and
+// scans all the returned rows into dest, a pointer to a slice.
+//
+// See SelectBuilder.ScanAll for the slices dest can point to.
+func (b InsertBuilder) ScanAll(dest interface{}) error {
+	data := builder.GetStruct(b).(insertData)
+	rows, err := data.Query()
+	if err != nil {
+		return err
+	}
+	return scanAll(rows, dest, false)
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -231,6 +350,17 @@ func (b InsertBuilder) Values(values ...interface{}) InsertBuilder {
 	return builder.Append(b, "Values", values).(InsertBuilder)
 }
 
+// Returning adds a clause returning columns of the inserted rows, e.g. to
+// fetch generated IDs, rendered as a RETURNING clause or, on SQL Server, as an
+// OUTPUT clause. It returns an error on dialects supporting neither, such as
+// MySQL.
+//
+// Run the statement with Query, QueryRow, ScanStruct or ScanAll to read the
+// returned rows.
+func (b InsertBuilder) Returning(columns ...string) InsertBuilder {
+	return builder.Extend(b, "Returning", columns).(InsertBuilder)
+}
+
 // Suffix adds an expression to the end of the query
 func (b InsertBuilder) Suffix(sql string, args ...interface{}) InsertBuilder {
 	return builder.Append(b, "Suffixes", Expr(sql, args...)).(InsertBuilder)
@@ -257,12 +387,91 @@ func (b InsertBuilder) SetMap(clauses map[string]interface{}) InsertBuilder {
 	return b
 }
 
+// SetStruct sets columns and values for insert builder from v, a struct or a
+// slice of structs for a multi-row insert, mapping fields to columns with
+// their db tags (see structTag). Read-only columns are not inserted, nor are
+// omitempty columns that are empty in every struct; ToSql returns an error for
+// those empty in some of the structs only.
+// Note that it will reset all previous columns and values was set if any.
+func (b InsertBuilder) SetStruct(v interface{}) InsertBuilder {
+	columns, rows, err := structColumns(v, true)
+	if err != nil {
+		return builder.Set(b, "StructErr", err).(InsertBuilder)
+	}
+
+	b = builder.Delete(b, "StructErr").(InsertBuilder)
+	b = builder.Set(b, "Columns", columns).(InsertBuilder)
+	b = builder.Set(b, "Values", rows).(InsertBuilder)
+
+	return b
+}
+
 // Select set Select clause for insert query
 // If Values and Select are used, then Select has higher priority
 func (b InsertBuilder) Select(sb SelectBuilder) InsertBuilder {
 	return builder.Set(b, "Select", &sb).(InsertBuilder)
 }
 
+// OnConflict sets the conflict target columns of the upsert clause.
+//
+// The target is required by DoUpdateSet with OnConflictUpsert and is used as
+// the no-op column of DoNothing with OnDuplicateKeyUpsert, where MySQL picks
+// the conflicting unique key itself.
+func (b InsertBuilder) OnConflict(columns ...string) InsertBuilder {
+	return builder.Extend(b, "ConflictColumns", columns).(InsertBuilder)
+}
+
+// OnConflictConstraint sets a named constraint as the conflict target of the
+// upsert clause, e.g. ON CONFLICT ON CONSTRAINT users_email_key. Only
+// PostgreSQL supports this; OnDuplicateKeyUpsert returns an error.
+func (b InsertBuilder) OnConflictConstraint(name string) InsertBuilder {
+	return builder.Set(b, "ConflictConstraint", name).(InsertBuilder)
+}
+
+// DoNothing makes the query skip rows that conflict with existing ones.
+func (b InsertBuilder) DoNothing() InsertBuilder {
+	return builder.Set(b, "ConflictDoNothing", true).(InsertBuilder)
+}
+
+// DoUpdateSet adds a SET clause applied to existing rows that conflict with the
+// inserted ones. value may be Excluded(column) to reference the value proposed
+// for insertion, a Sqlizer, or a value bound to a placeholder.
+func (b InsertBuilder) DoUpdateSet(column string, value interface{}) InsertBuilder {
+	return builder.Append(b, "ConflictSetClauses", setClause{column: column, value: value}).(InsertBuilder)
+}
+
+// DoUpdateSetMap is a convenience method which calls .DoUpdateSet for each
+// key/value pair in clauses.
+func (b InsertBuilder) DoUpdateSetMap(clauses map[string]interface{}) InsertBuilder {
+	keys := make([]string, 0, len(clauses))
+	for key := range clauses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b = b.DoUpdateSet(key, clauses[key])
+	}
+	return b
+}
+
+// DoUpdateSetExcluded is a convenience method which overwrites each of columns
+// with the value proposed for insertion, i.e. calls
+// .DoUpdateSet(column, Excluded(column)).
+func (b InsertBuilder) DoUpdateSetExcluded(columns ...string) InsertBuilder {
+	for _, column := range columns {
+		b = b.DoUpdateSet(column, Excluded(column))
+	}
+	return b
+}
+
+// DoUpdateWhere adds WHERE expressions restricting which conflicting rows are
+// updated. It is not supported by OnDuplicateKeyUpsert.
+//
+// See SelectBuilder.Where for more information.
+func (b InsertBuilder) DoUpdateWhere(pred interface{}, args ...interface{}) InsertBuilder {
+	return builder.Append(b, "ConflictWhereParts", newWherePart(pred, args...)).(InsertBuilder)
+}
+
 func (b InsertBuilder) statementKeyword(keyword string) InsertBuilder {
 	return builder.Set(b, "StatementKeyword", keyword).(InsertBuilder)
 }
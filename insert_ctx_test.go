@@ -39,3 +39,17 @@ func TestInsertBuilderContextNoRunner(t *testing.T) {
 	err = b.ScanContext(ctx)
 	assert.Equal(t, RunnerNotSet, err)
 }
+
+func TestInsertBuilderExecBatchContext(t *testing.T) {
+	db := &DBStub{}
+	_, err := batchRows(2).BatchSize(1).RunWith(db).ExecBatchContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a,b,c) VALUES (?,?,?)", db.LastExecSql)
+	assert.Equal(t, []interface{}{1, 1, 1}, db.LastExecArgs)
+
+	_, err = batchRows(2).RunWith(struct {
+		Execer
+		Queryer
+	}{db, db}).ExecBatchContext(ctx)
+	assert.Equal(t, NoContextSupport, err)
+}
@@ -30,7 +30,9 @@ const (
 )
 
 var (
-	sqrl StatementBuilderType
+	sqrl        StatementBuilderType
+	testDB      *sql.DB
+	testDialect Dialect
 )
 
 func TestMain(m *testing.M) {
@@ -40,7 +42,9 @@ func TestMain(m *testing.M) {
 	flag.Parse()
 
 	if driver == "sqlite3" && dataSource == "" {
-		dataSource = ":memory:"
+		// A shared cache lets every connection of the pool, such as the
+		// one of a transaction, see the same in-memory database.
+		dataSource = "file::memory:?cache=shared"
 	}
 
 	db, err := sql.Open(driver, dataSource)
@@ -67,6 +71,8 @@ func TestMain(m *testing.M) {
 	}
 
 	sqrl = StatementBuilder.RunWith(db)
+	testDB = db
+	testDialect = map[string]Dialect{"postgres": PostgreSQL, "mysql": MySQL, "sqlite3": SQLite}[driver]
 
 	if driver == "postgres" {
 		sqrl = sqrl.PlaceholderFormat(Dollar)
@@ -129,3 +135,116 @@ func TestContext(t *testing.T) {
 	_, err := s.QueryContext(ctx)
 	assert.NoError(t, err)
 }
+
+func TestQueryPage(t *testing.T) {
+	s := sqrl.Select("k").From("squirrel_integration").OrderBy("k")
+
+	for _, count := range []PageCount{CountSeparately, CountOver} {
+		var ks []int
+		scan := func(row RowScanner) error {
+			var k int
+			err := row.Scan(&k)
+			ks = append(ks, k)
+			return err
+		}
+
+		result, err := s.Limit(3).QueryPage(count, scan)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, ks)
+		assert.Equal(t, PageResult{Total: 4, Page: 1, PerPage: 3, Rows: 3, HasNext: true}, result)
+
+		ks = nil
+		result, err = s.Limit(3).Offset(3).QueryPageContext(context.Background(), count, scan)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{4}, ks)
+		assert.Equal(t, PageResult{Total: 4, Page: 2, PerPage: 3, Rows: 1, HasNext: false}, result)
+
+		ks = nil
+		result, err = s.Limit(2).Offset(4).QueryPage(count, scan)
+		assert.NoError(t, err)
+		assert.Empty(t, ks)
+		assert.Equal(t, PageResult{Total: 4, Page: 3, PerPage: 2, Rows: 0, HasNext: false}, result)
+	}
+}
+
+type integrationRow struct {
+	K int     `db:"k"`
+	V *string `db:"v"`
+}
+
+func TestScanStruct(t *testing.T) {
+	s := sqrl.Select("k", "v").From("squirrel_integration")
+
+	var row integrationRow
+	assert.NoError(t, s.Where(Eq{"k": 3}).ScanStruct(&row))
+	assert.Equal(t, 3, row.K)
+	assert.Equal(t, "bar", *row.V)
+
+	assert.Equal(t, sql.ErrNoRows, s.Where(Eq{"k": 5}).ScanStruct(&row))
+
+	var m map[string]interface{}
+	assert.NoError(t, s.Where(Eq{"k": 4}).ScanStructContext(context.Background(), &m))
+	assert.Equal(t, 2, len(m))
+
+	var k int
+	assert.NoError(t, sqrl.Select("MAX(k)").From("squirrel_integration").ScanStruct(&k))
+	assert.Equal(t, 4, k)
+}
+
+func TestScanAll(t *testing.T) {
+	s := sqrl.Select("k", "v", "k * 2 AS double").From("squirrel_integration").OrderBy("k")
+
+	var rows []*integrationRow
+	assert.NoError(t, s.ScanAll(&rows))
+	assert.Equal(t, 4, len(rows))
+	assert.Equal(t, 2, rows[1].K)
+	assert.Equal(t, "foo", *rows[1].V)
+
+	var strictRows []integrationRow
+	assert.Error(t, s.StrictScan().ScanAllContext(context.Background(), &strictRows))
+
+	var ks []int
+	assert.NoError(t, sqrl.Select("k").From("squirrel_integration").OrderBy("k").ScanAll(&ks))
+	assert.Equal(t, []int{1, 2, 3, 4}, ks)
+}
+
+func TestExecBatch(t *testing.T) {
+	b := sqrl.Insert("squirrel_integration").Columns("k", "v").BatchSize(300).BatchTx()
+	for k := 100; k < 1100; k++ {
+		b = b.Values(k, "batch")
+	}
+	defer sqrl.Delete("squirrel_integration").Where(Eq{"v": "batch"}).Exec()
+
+	result, err := b.ExecBatch()
+	assert.NoError(t, err)
+	rows, err := result.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), rows)
+
+	var count int
+	err = sqrl.Select("COUNT(*)").From("squirrel_integration").Where(Eq{"v": "batch"}).Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, count)
+}
+
+func TestInterpolatedRoundTrip(t *testing.T) {
+	q := sqrl.RunWith(NewInterpolatingRunner(testDB, testDialect))
+	if testDialect != nil {
+		q = q.Dialect(testDialect)
+	}
+	v := "it's a \\ 'quoted' -- value"
+	defer sqrl.Delete("squirrel_integration").Where(Eq{"k": 100}).Exec()
+
+	_, err := q.Insert("squirrel_integration").Columns("k", "v").Values(100, v).Exec()
+	assert.NoError(t, err)
+
+	var got string
+	err = sqrl.Select("v").From("squirrel_integration").Where(Eq{"k": 100}).Scan(&got)
+	assert.NoError(t, err)
+	assert.Equal(t, v, got)
+
+	var k int
+	err = q.Select("k").From("squirrel_integration").Where(Eq{"v": v}).Scan(&k)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, k)
+}
@@ -0,0 +1,272 @@
+package squirrel
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interpolate calls ToSql on s and returns its SQL with its arguments
+// written as literals of d, for logging or for engines and poolers that
+// cannot bind arguments, e.g. PgBouncer in transaction mode.
+//
+// Unlike DebugSqlizer, Interpolate escapes the arguments it writes, and
+// returns an error for arguments it cannot write safely. Accepted arguments
+// are nil, strings, []byte, bools, numbers, time.Time values, types whose
+// underlying type is one of these, pointers to them and driver.Valuers.
+// MySQL strings are written for servers without the NO_BACKSLASH_ESCAPES SQL
+// mode, and MySQL times in UTC, as the MySQL driver does by default. For the
+// same reason, backslashes escape quotes in the string literals of MySQL
+// queries, and queries with an unterminated quoted string are refused.
+//
+// The placeholders of s are those of its PlaceholderFormat when s is one of
+// the builders, and question marks otherwise.
+func Interpolate(d Dialect, s Sqlizer) (string, error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return "", err
+	}
+	prefix := "?"
+	if downCast, ok := s.(placeholderDebugger); ok {
+		prefix = downCast.debugPlaceholder()
+	}
+	return interpolate(d, prefix, query, args)
+}
+
+// InterpolateSql returns query, whose placeholders are in the
+// PlaceholderFormat of d, with args written as literals of d as by
+// Interpolate.
+func InterpolateSql(d Dialect, query string, args ...interface{}) (string, error) {
+	return interpolate(d, debugPlaceholder(dialectOr(d).PlaceholderFormat()), query, args)
+}
+
+func interpolate(d Dialect, prefix, query string, args []interface{}) (string, error) {
+	d = dialectOr(d)
+	if prefix == "?" {
+		prefix = ""
+	}
+	// Arguments must be written where d reads placeholders, not inside its
+	// string literals: MySQL reads backslash escapes in all of them.
+	backslashes := backslashNever
+	if d.literals() == literalsMySQL {
+		backslashes = backslashAlways
+	}
+	if unterminatedString(query, backslashes == backslashAlways) {
+		return "", fmt.Errorf("unterminated quoted string in %q", query)
+	}
+	named := make(map[string]bool)
+	sql, n, err := rewriteParams(query, prefix, backslashes, func(buf *bytes.Buffer, i int, name string) error {
+		if len(name) > 0 {
+			if i = namedArgIndex(args, name); i < 0 {
+				// a variable, e.g. SQL Server's DECLARE @name
+				buf.WriteString("@" + name)
+				return nil
+			}
+			named[name] = true
+		}
+		if i < 0 || i >= len(args) {
+			return fmt.Errorf("too many placeholders in %q for %d args", query, len(args))
+		}
+		literal, err := sqlLiteral(d, namedArgValue(args[i]))
+		if err != nil {
+			return fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		buf.WriteString(literal)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if n+len(named) < len(args) {
+		return "", fmt.Errorf("not enough placeholders in %q for %d args", query, len(args))
+	}
+	return sql, nil
+}
+
+// sqlLiteral returns v written as a literal of d.
+func sqlLiteral(d Dialect, v interface{}) (string, error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return "NULL", nil
+		}
+		value, err := valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		if _, ok := value.(driver.Valuer); ok {
+			return "", fmt.Errorf("%T.Value returned a driver.Valuer", v)
+		}
+		return sqlLiteral(d, value)
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return stringLiteral(d, v)
+	case []byte:
+		if v == nil {
+			return "NULL", nil
+		}
+		return bytesLiteral(d, v), nil
+	case bool:
+		return boolLiteral(d, v), nil
+	case time.Time:
+		return timeLiteral(d, v), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return "NULL", nil
+		}
+		return sqlLiteral(d, rv.Elem().Interface())
+	case reflect.String:
+		return stringLiteral(d, rv.String())
+	case reflect.Bool:
+		return boolLiteral(d, rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return numberLiteral(strconv.FormatInt(rv.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", fmt.Errorf("cannot write %v as a SQL literal", f)
+		}
+		return numberLiteral(strconv.FormatFloat(f, 'g', -1, rv.Type().Bits())), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return sqlLiteral(d, rv.Bytes())
+		}
+	}
+	return "", fmt.Errorf("cannot write a %T as a SQL literal", v)
+}
+
+// numberLiteral parenthesizes negative numbers, so that e.g. x-? does not
+// become a comment, x--1.
+func numberLiteral(n string) string {
+	if strings.HasPrefix(n, "-") {
+		return "(" + n + ")"
+	}
+	return n
+}
+
+func stringLiteral(d Dialect, s string) (string, error) {
+	style := d.literals()
+	if style != literalsMySQL && strings.IndexByte(s, 0) >= 0 {
+		return "", fmt.Errorf("cannot write a string with a NUL byte as a %s literal", d.Name())
+	}
+
+	s = strings.Replace(s, "'", "''", -1)
+	switch style {
+	case literalsMySQL:
+		s = strings.NewReplacer(`\`, `\\`, "\x00", `\0`, "\n", `\n`, "\r", `\r`, "\x1a", `\Z`).Replace(s)
+	case literalsSQLServer:
+		return "N'" + s + "'", nil
+	}
+	return "'" + s + "'", nil
+}
+
+func bytesLiteral(d Dialect, b []byte) string {
+	switch d.literals() {
+	case literalsPostgres:
+		return `'\x` + hex.EncodeToString(b) + `'::bytea`
+	case literalsOracle:
+		return "HEXTORAW('" + hex.EncodeToString(b) + "')"
+	case literalsSQLServer:
+		return "0x" + hex.EncodeToString(b)
+	}
+	return "X'" + hex.EncodeToString(b) + "'"
+}
+
+func boolLiteral(d Dialect, b bool) string {
+	switch d.literals() {
+	case literalsSQLite, literalsOracle, literalsSQLServer:
+		if b {
+			return "1"
+		}
+		return "0"
+	}
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func timeLiteral(d Dialect, t time.Time) string {
+	switch d.literals() {
+	case literalsMySQL:
+		return "'" + t.UTC().Format("2006-01-02 15:04:05.999999") + "'"
+	case literalsOracle:
+		return "TIMESTAMP '" + t.Format("2006-01-02 15:04:05.999999999 -07:00") + "'"
+	case literalsSQLServer:
+		return "'" + t.Format("2006-01-02T15:04:05.9999999-07:00") + "'"
+	}
+	return "'" + t.Format("2006-01-02 15:04:05.999999999-07:00") + "'"
+}
+
+// InterpolatingRunner is a Runner sending the statements it runs to another
+// one with their arguments written as literals of its Dialect, as by
+// InterpolateSql, and no arguments. Statements that cannot be interpolated
+// are not run, and return a *BuildError.
+type InterpolatingRunner struct {
+	runner  BaseRunner
+	dialect Dialect
+}
+
+// NewInterpolatingRunner returns an InterpolatingRunner running statements,
+// whose placeholders are in the PlaceholderFormat of d, with runner.
+func NewInterpolatingRunner(runner BaseRunner, d Dialect) *InterpolatingRunner {
+	if db, ok := runner.(stdsql); ok {
+		runner = &stdsqlRunner{db}
+	}
+	return &InterpolatingRunner{runner: runner, dialect: d}
+}
+
+func (r *InterpolatingRunner) interpolate(query string, args []interface{}) (string, error) {
+	query, err := InterpolateSql(r.dialect, query, args...)
+	if err != nil {
+		return "", &BuildError{Err: err}
+	}
+	return query, nil
+}
+
+// Exec interpolates query and Execs it with the wrapped runner.
+func (r *InterpolatingRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	query, err := r.interpolate(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return r.runner.Exec(query)
+}
+
+// Query interpolates query and Querys it with the wrapped runner.
+func (r *InterpolatingRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	query, err := r.interpolate(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return r.runner.Query(query)
+}
+
+// QueryRow interpolates query and QueryRows it with the wrapped runner.
+func (r *InterpolatingRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.runner.(QueryRower)
+	if !ok {
+		return &Row{err: RunnerNotQueryRunner}
+	}
+	query, err := r.interpolate(query, args)
+	if err != nil {
+		return &Row{err: err}
+	}
+	return queryRower.QueryRow(query)
+}
@@ -0,0 +1,53 @@
+//go:build go1.8
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ExecContext interpolates query and ExecContexts it with the wrapped runner.
+func (r *InterpolatingRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctxRunner, ok := r.runner.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	query, err := r.interpolate(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return ctxRunner.ExecContext(ctx, query)
+}
+
+// QueryContext interpolates query and QueryContexts it with the wrapped
+// runner.
+func (r *InterpolatingRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctxRunner, ok := r.runner.(QueryerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	query, err := r.interpolate(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return ctxRunner.QueryContext(ctx, query)
+}
+
+// QueryRowContext interpolates query and QueryRowContexts it with the wrapped
+// runner.
+func (r *InterpolatingRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.runner.(QueryRowerContext)
+	if !ok {
+		if _, ok := r.runner.(QueryerContext); !ok {
+			return &Row{err: RunnerNotQueryRunner}
+		}
+		return &Row{err: NoContextSupport}
+	}
+	query, err := r.interpolate(query, args)
+	if err != nil {
+		return &Row{err: err}
+	}
+	return queryRower.QueryRowContext(ctx, query)
+}
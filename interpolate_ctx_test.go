@@ -0,0 +1,34 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolatingRunnerContext(t *testing.T) {
+	db := &DBStub{}
+	q := StatementBuilder.Dialect(MySQL).RunWith(NewInterpolatingRunner(db, MySQL))
+
+	_, err := q.Delete("t").Where(Eq{"a": `x\'`}).ExecContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, `DELETE FROM t WHERE a = 'x\\'''`, db.LastExecSql)
+	assert.Empty(t, db.LastExecArgs)
+
+	q.Select("a").From("t").Where("b = ?", 1).QueryContext(ctx)
+	assert.Equal(t, "SELECT a FROM t WHERE b = 1", db.LastQuerySql)
+
+	q.Select("a").From("t").Where("b = ?", false).QueryRowContext(ctx)
+	assert.Equal(t, "SELECT a FROM t WHERE b = FALSE", db.LastQueryRowSql)
+
+	_, err = q.Select("a").From("t").Where("b = ?", struct{}{}).QueryContext(ctx)
+	assert.True(t, IsConfigError(err))
+
+	_, err = NewInterpolatingRunner(struct {
+		Execer
+		Queryer
+	}{db, db}, MySQL).ExecContext(ctx, "SELECT 1")
+	assert.Equal(t, NoContextSupport, err)
+}
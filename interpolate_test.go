@@ -0,0 +1,180 @@
+package squirrel
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type status string
+
+func TestInterpolate(t *testing.T) {
+	q := Select("*").
+		From("t").
+		Where("a = ? AND b = ? AND c = ? AND d = ?", "it's", 42, -1.5, nil).
+		Where("e = ? AND f = ? AND g IN (?)", true, uint8(7), status("open")).
+		Where("data ? 'k' AND note <> 'why?'")
+
+	query, err := Interpolate(PostgreSQL, q.Dialect(PostgreSQL))
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT * FROM t WHERE a = 'it''s' AND b = 42 AND c = (-1.5) AND d = NULL "+
+			"AND e = TRUE AND f = 7 AND g IN ('open') AND data ? 'k' AND note <> 'why?'",
+		query)
+
+	query, err = Interpolate(SQLite, q)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT * FROM t WHERE a = 'it''s' AND b = 42 AND c = (-1.5) AND d = NULL "+
+			"AND e = 1 AND f = 7 AND g IN ('open') AND data ? 'k' AND note <> 'why?'",
+		query)
+}
+
+func TestInterpolateLiterals(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.FixedZone("", 2*60*60))
+	s := "it's a \\ \n"
+	n := int64(-3)
+	var nilPtr *int64
+	tests := []struct {
+		d        Dialect
+		value    interface{}
+		expected string
+	}{
+		{PostgreSQL, s, "'it''s a \\ \n'"},
+		{MySQL, s + "\x00\x1a", `'it''s a \\ \n\0\Z'`},
+		{SQLServer, "héllo", "N'héllo'"},
+		{Oracle, s, "'it''s a \\ \n'"},
+		{PostgreSQL, []byte{0xbe, 0xef}, `'\xbeef'::bytea`},
+		{MySQL, []byte{0xbe, 0xef}, "X'beef'"},
+		{SQLite, []byte{}, "X''"},
+		{Oracle, []byte{0xbe, 0xef}, "HEXTORAW('beef')"},
+		{SQLServer, []byte{0xbe, 0xef}, "0xbeef"},
+		{PostgreSQL, []byte(nil), "NULL"},
+		{PostgreSQL, false, "FALSE"},
+		{Oracle, true, "1"},
+		{nil, true, "TRUE"},
+		{PostgreSQL, ts, "'2020-01-02 03:04:05.123456+02:00'"},
+		{MySQL, ts, "'2020-01-02 01:04:05.123456'"},
+		{Oracle, ts, "TIMESTAMP '2020-01-02 03:04:05.123456 +02:00'"},
+		{SQLServer, ts, "'2020-01-02T03:04:05.123456+02:00'"},
+		{PostgreSQL, &n, "(-3)"},
+		{PostgreSQL, nilPtr, "NULL"},
+		{PostgreSQL, uint64(math.MaxUint64), "18446744073709551615"},
+		{PostgreSQL, float32(0.1), "0.1"},
+		{PostgreSQL, 1e21, "1e+21"},
+		{PostgreSQL, sql.NullString{String: "x", Valid: true}, "'x'"},
+		{PostgreSQL, sql.NullInt64{}, "NULL"},
+		{PostgreSQL, (*sql.NullInt64)(nil), "NULL"},
+		{PostgreSQL, sql.Named("a", 1), "1"},
+	}
+	for _, test := range tests {
+		query, err := InterpolateSql(test.d, placeholderFor(test.d), test.value)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, query, "%s %#v", dialectOr(test.d).Name(), test.value)
+	}
+}
+
+// placeholderFor returns a placeholder in the PlaceholderFormat of d.
+func placeholderFor(d Dialect) string {
+	query, _ := dialectOr(d).PlaceholderFormat().ReplacePlaceholders("?")
+	return query
+}
+
+func TestInterpolateAfterWords(t *testing.T) {
+	query, err := InterpolateSql(MySQL, "SELECT GROUP_CONCAT(x SEPARATOR ?) FROM t", ", ")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT GROUP_CONCAT(x SEPARATOR ', ') FROM t", query)
+
+	query, err = InterpolateSql(PostgreSQL, "SELECT SUM(x) OVER (ROWS $1 PRECEDING), data ? 'k' FROM t", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT SUM(x) OVER (ROWS 3 PRECEDING), data ? 'k' FROM t", query)
+}
+
+func TestInterpolateBackslashEscapes(t *testing.T) {
+	query, err := InterpolateSql(MySQL, `SELECT * FROM t WHERE a = 'it\'s?' AND b = ?`, "x")
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM t WHERE a = 'it\'s?' AND b = 'x'`, query)
+
+	query, err = InterpolateSql(PostgreSQL, `SELECT * FROM t WHERE a = 'C:\' AND b = $1`, "x")
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM t WHERE a = 'C:\' AND b = 'x'`, query)
+
+	// MySQL reads the ? as part of the string: it must not be written there.
+	_, err = InterpolateSql(MySQL, `SELECT * FROM t WHERE a = 'x\', ?, ' AND b = 'y'`, "p' OR 1=1 -- ")
+	assert.EqualError(t, err, `not enough placeholders in "SELECT * FROM t WHERE a = 'x\\', ?, ' AND b = 'y'" for 1 args`)
+
+	_, err = InterpolateSql(MySQL, `SELECT * FROM t WHERE a = 'x\' AND b = ?`, "p")
+	assert.EqualError(t, err, `unterminated quoted string in "SELECT * FROM t WHERE a = 'x\\' AND b = ?"`)
+}
+
+func TestInterpolateNamedArgs(t *testing.T) {
+	b := Update("t").
+		Set("a", 1).
+		Where("id = @id AND owner = @owner", sql.Named("id", 7), sql.Named("owner", "me")).
+		Suffix("SELECT @rows").
+		Dialect(SQLServer)
+
+	query, err := Interpolate(SQLServer, b)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = 1 WHERE id = 7 AND owner = N'me' SELECT @rows", query)
+
+	_, err = InterpolateSql(SQLServer, "a = @p1", 1, sql.Named("b", 2))
+	assert.EqualError(t, err, `not enough placeholders in "a = @p1" for 2 args`)
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	_, err := InterpolateSql(PostgreSQL, "a = $1", struct{}{})
+	assert.EqualError(t, err, "argument 1: cannot write a struct {} as a SQL literal")
+
+	_, err = InterpolateSql(PostgreSQL, "a = $1", []int{1})
+	assert.EqualError(t, err, "argument 1: cannot write a []int as a SQL literal")
+
+	_, err = InterpolateSql(MySQL, "a = ?", math.NaN())
+	assert.EqualError(t, err, "argument 1: cannot write NaN as a SQL literal")
+
+	_, err = InterpolateSql(PostgreSQL, "a = $1", "a\x00b")
+	assert.EqualError(t, err, "argument 1: cannot write a string with a NUL byte as a postgres literal")
+
+	_, err = InterpolateSql(MySQL, "a = ? AND b = ?", 1)
+	assert.EqualError(t, err, `too many placeholders in "a = ? AND b = ?" for 1 args`)
+
+	_, err = InterpolateSql(MySQL, "a = ?", 1, 2)
+	assert.EqualError(t, err, `not enough placeholders in "a = ?" for 2 args`)
+
+	_, err = Interpolate(MySQL, Select())
+	assert.Error(t, err)
+}
+
+func TestInterpolatingRunner(t *testing.T) {
+	db := &DBStub{}
+	runner := NewInterpolatingRunner(db, PostgreSQL)
+	q := StatementBuilder.Dialect(PostgreSQL).RunWith(runner)
+
+	_, err := q.Update("t").Set("a", "x").Where(Eq{"id": 1}).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = 'x' WHERE id = 1", db.LastExecSql)
+	assert.Empty(t, db.LastExecArgs)
+
+	q.Select("a").From("t").Where("b = ?", []byte("x")).Query()
+	assert.Equal(t, `SELECT a FROM t WHERE b = '\x78'::bytea`, db.LastQuerySql)
+	assert.Empty(t, db.LastQueryArgs)
+
+	q.Select("a").From("t").Where("b = ?", "y").QueryRow()
+	assert.Equal(t, "SELECT a FROM t WHERE b = 'y'", db.LastQueryRowSql)
+
+	_, err = q.Delete("t").Where("b = ?", struct{}{}).Exec()
+	assert.IsType(t, &BuildError{}, err)
+	assert.True(t, IsConfigError(err))
+
+	err = q.Select("a").From("t").Where("b = ?", struct{}{}).QueryRow().Scan()
+	assert.True(t, IsConfigError(err))
+
+	err = NewInterpolatingRunner(struct {
+		Execer
+		Queryer
+	}{db, db}, PostgreSQL).QueryRow("SELECT 1").Scan()
+	assert.Equal(t, RunnerNotQueryRunner, err)
+}
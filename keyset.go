@@ -0,0 +1,329 @@
+package squirrel
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SortColumn is a column of an ORDER BY clause together with its direction.
+type SortColumn struct {
+	Column string
+	Desc   bool
+	Nulls  NullsOrder
+
+	// whether Column is an expression whitelisted by SortFields, which
+	// strict mode does not check
+	trusted bool
+}
+
+// NullsOrder is the position of NULL values in the order of a SortColumn.
+type NullsOrder int
+
+const (
+	// NullsDefault leaves NULL values where the database sorts them.
+	NullsDefault NullsOrder = iota
+	// NullsFirst sorts NULL values before the other values.
+	NullsFirst
+	// NullsLast sorts NULL values after the other values.
+	NullsLast
+)
+
+// Asc returns a SortColumn sorting column in ascending order.
+func Asc(column string) SortColumn {
+	return SortColumn{Column: column}
+}
+
+// Desc returns a SortColumn sorting column in descending order.
+func Desc(column string) SortColumn {
+	return SortColumn{Column: column, Desc: true}
+}
+
+// NullsFirst returns c sorting NULL values first.
+func (c SortColumn) NullsFirst() SortColumn {
+	c.Nulls = NullsFirst
+	return c
+}
+
+// NullsLast returns c sorting NULL values last.
+func (c SortColumn) NullsLast() SortColumn {
+	c.Nulls = NullsLast
+	return c
+}
+
+func (c SortColumn) ToSql() (sql string, args []interface{}, err error) {
+	return c.toSqlDialect(nil)
+}
+
+func (c SortColumn) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	if len(c.Column) == 0 {
+		err = errors.New("sort columns must have a name")
+		return
+	}
+	sql = c.Column + " ASC"
+	if c.Desc {
+		sql = c.Column + " DESC"
+	}
+	if c.Nulls == NullsDefault {
+		return
+	}
+
+	if dialectOr(d).supportsNullsOrder() {
+		if c.Nulls == NullsFirst {
+			return sql + " NULLS FIRST", nil, nil
+		}
+		return sql + " NULLS LAST", nil, nil
+	}
+	// Sort on whether the values are NULL first.
+	nullRank := [2]int{1, 0}
+	if c.Nulls == NullsFirst {
+		nullRank = [2]int{0, 1}
+	}
+	sql = fmt.Sprintf("CASE WHEN %s IS NULL THEN %d ELSE %d END, %s", c.Column, nullRank[0], nullRank[1], sql)
+	return
+}
+
+// op returns the operator selecting the rows sorted after a value.
+func (c SortColumn) op() string {
+	if c.Desc {
+		return "<"
+	}
+	return ">"
+}
+
+// after returns the condition selecting the rows sorted after the value v of
+// the column, and false if no row can be, i.e. v is NULL and NULL values are
+// sorted last.
+func (c SortColumn) after(v interface{}) (string, []interface{}, bool) {
+	switch {
+	case v == nil && c.Nulls == NullsLast:
+		return "", nil, false
+	case v == nil:
+		return c.Column + " IS NOT NULL", nil, true
+	case c.Nulls == NullsLast:
+		return fmt.Sprintf("(%s %s ? OR %s IS NULL)", c.Column, c.op(), c.Column), []interface{}{v}, true
+	default:
+		return fmt.Sprintf("%s %s ?", c.Column, c.op()), []interface{}{v}, true
+	}
+}
+
+// equal returns the condition selecting the rows with the value v of the
+// column.
+func (c SortColumn) equal(v interface{}) (string, []interface{}) {
+	if v == nil {
+		return c.Column + " IS NULL", nil
+	}
+	return c.Column + " = ?", []interface{}{v}
+}
+
+// keysetPredicate selects the rows sorted after the cursor values by columns.
+type keysetPredicate struct {
+	columns []SortColumn
+	cursor  []interface{}
+}
+
+func (p keysetPredicate) ToSql() (sql string, args []interface{}, err error) {
+	return p.toSqlDialect(nil)
+}
+
+func (p keysetPredicate) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
+	if len(p.columns) == 0 {
+		err = errors.New("keyset pagination needs at least one sort column")
+		return
+	}
+	if len(p.cursor) != len(p.columns) {
+		err = fmt.Errorf("keyset cursor has %d values for %d sort columns", len(p.cursor), len(p.columns))
+		return
+	}
+
+	rowValues := dialectOr(d).supportsRowValues()
+	columns := make([]string, len(p.columns))
+	for i, c := range p.columns {
+		if len(c.Column) == 0 {
+			err = errors.New("sort columns must have a name")
+			return
+		}
+		if p.cursor[i] == nil && c.Nulls == NullsDefault {
+			// Where NULL values are sorted is not known, so neither are the
+			// rows that follow.
+			err = fmt.Errorf("keyset cursor value of %s is NULL; sort it with NullsFirst or NullsLast", c.Column)
+			return
+		}
+		// Row values do not compare NULL values as they are sorted.
+		rowValues = rowValues && c.Desc == p.columns[0].Desc && c.Nulls == NullsDefault
+		columns[i] = c.Column
+	}
+
+	if rowValues && len(p.columns) > 1 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+		sql = fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), p.columns[0].op(), placeholders)
+		return sql, p.cursor, nil
+	}
+
+	// (a > ? OR (a = ? AND b > ?) OR (a = ? AND b = ? AND c > ?))
+	var levels []string
+	for i, c := range p.columns {
+		after, afterArgs, ok := c.after(p.cursor[i])
+		if !ok {
+			continue
+		}
+		var conds []string
+		for j := 0; j < i; j++ {
+			equal, equalArgs := p.columns[j].equal(p.cursor[j])
+			conds = append(conds, equal)
+			args = append(args, equalArgs...)
+		}
+		conds = append(conds, after)
+		args = append(args, afterArgs...)
+
+		level := strings.Join(conds, " AND ")
+		if len(conds) > 1 {
+			level = "(" + level + ")"
+		}
+		levels = append(levels, level)
+	}
+
+	switch len(levels) {
+	case 0:
+		return dialectOr(d).BoolLiteral(false), nil, nil
+	case 1:
+		return levels[0], args, nil
+	default:
+		return "(" + strings.Join(levels, " OR ") + ")", args, nil
+	}
+}
+
+// InvalidCursor is returned by DecodeCursor for tokens it cannot decode.
+var InvalidCursor = errors.New("invalid cursor")
+
+// cursorValue is a value of an encoded cursor, tagged with its type so that
+// it decodes to the type it was encoded from.
+type cursorValue struct {
+	Type  string          `json:"t"`
+	Value json.RawMessage `json:"v,omitempty"`
+}
+
+// EncodeCursor encodes the values of the sort columns of the last row of a
+// page into an opaque, URL-safe token for DecodeCursor.
+//
+// Values can be nil, bools, integers, floats, strings, []byte, time.Time or
+// driver.Valuers returning one of those. Integers decode as int64 or uint64,
+// floats as float64.
+func EncodeCursor(values ...interface{}) (string, error) {
+	encoded := make([]cursorValue, len(values))
+	for i, v := range values {
+		cv, err := encodeCursorValue(v)
+		if err != nil {
+			return "", err
+		}
+		encoded[i] = cv
+	}
+	b, err := json.Marshal(encoded)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func encodeCursorValue(v interface{}) (cv cursorValue, err error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		if v, err = valuer.Value(); err != nil {
+			return
+		}
+	}
+
+	switch v := v.(type) {
+	case nil:
+		cv.Type = "n"
+		return
+	case bool:
+		cv.Type = "b"
+	case int, int8, int16, int32, int64:
+		cv.Type = "i"
+	case uint, uint8, uint16, uint32, uint64:
+		cv.Type = "u"
+	case float32, float64:
+		cv.Type = "f"
+	case string:
+		cv.Type = "s"
+	case []byte:
+		cv.Type = "x"
+	case time.Time:
+		cv.Type = "t"
+		cv.Value, err = json.Marshal(v.Format(time.RFC3339Nano))
+		return
+	default:
+		err = fmt.Errorf("cannot encode cursor value of type %T", v)
+		return
+	}
+	cv.Value, err = json.Marshal(v)
+	return
+}
+
+// DecodeCursor decodes a token returned by EncodeCursor into the values it
+// was encoded from, e.g. to pass them to SelectBuilder.Seek. It returns an
+// error wrapping InvalidCursor if the token is malformed.
+func DecodeCursor(token string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", InvalidCursor, err)
+	}
+	var encoded []cursorValue
+	if err := json.Unmarshal(b, &encoded); err != nil {
+		return nil, fmt.Errorf("%w: %v", InvalidCursor, err)
+	}
+
+	values := make([]interface{}, len(encoded))
+	for i, cv := range encoded {
+		v, err := decodeCursorValue(cv)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", InvalidCursor, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func decodeCursorValue(cv cursorValue) (interface{}, error) {
+	var err error
+	switch cv.Type {
+	case "n":
+		return nil, nil
+	case "b":
+		var v bool
+		err = json.Unmarshal(cv.Value, &v)
+		return v, err
+	case "i":
+		var v int64
+		err = json.Unmarshal(cv.Value, &v)
+		return v, err
+	case "u":
+		var v uint64
+		err = json.Unmarshal(cv.Value, &v)
+		return v, err
+	case "f":
+		var v float64
+		err = json.Unmarshal(cv.Value, &v)
+		return v, err
+	case "s":
+		var v string
+		err = json.Unmarshal(cv.Value, &v)
+		return v, err
+	case "x":
+		var v []byte
+		err = json.Unmarshal(cv.Value, &v)
+		return v, err
+	case "t":
+		var s string
+		if err = json.Unmarshal(cv.Value, &s); err != nil {
+			return nil, err
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	default:
+		return nil, fmt.Errorf("unknown cursor value type %q", cv.Type)
+	}
+}
@@ -0,0 +1,131 @@
+package squirrel
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeekFirstPage(t *testing.T) {
+	sql, args, err := Select("id").From("users").
+		Seek([]SortColumn{Desc("created_at"), Asc("id")}).
+		Limit(10).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY created_at DESC, id ASC LIMIT 10", sql)
+	assert.Empty(t, args)
+}
+
+func TestSeekExpanded(t *testing.T) {
+	sql, args, err := Select("id").From("users").
+		Where(Eq{"active": true}).
+		Seek([]SortColumn{Desc("created_at"), Asc("name"), Asc("id")}, "2020-01-01", "moe", 42).
+		Limit(10).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT id FROM users WHERE active = $1 AND " +
+		"(created_at < $2 OR (created_at = $3 AND name > $4) OR (created_at = $5 AND name = $6 AND id > $7)) " +
+		"ORDER BY created_at DESC, name ASC, id ASC LIMIT 10"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{true, "2020-01-01", "2020-01-01", "moe", "2020-01-01", "moe", 42}, args)
+}
+
+func TestSeekRowValues(t *testing.T) {
+	b := Select("id").From("users").Seek([]SortColumn{Desc("name"), Desc("id")}, "moe", 42)
+
+	sql, args, err := b.Dialect(PostgreSQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE (name, id) < ($1, $2) ORDER BY name DESC, id DESC", sql)
+	assert.Equal(t, []interface{}{"moe", 42}, args)
+
+	sql, _, err = b.Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE (name < @p1 OR (name = @p2 AND id < @p3)) ORDER BY name DESC, id DESC", sql)
+}
+
+func TestSeekSingleColumn(t *testing.T) {
+	sql, args, err := Select("id").From("users").Seek([]SortColumn{Asc("id")}, 42).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE id > ? ORDER BY id ASC", sql)
+	assert.Equal(t, []interface{}{42}, args)
+}
+
+func TestSeekNulls(t *testing.T) {
+	columns := []SortColumn{Asc("name").NullsLast(), Desc("age").NullsFirst(), Asc("id")}
+
+	sql, args, err := Select("id").From("users").Seek(columns, "moe", 42, 7).Dialect(PostgreSQL).ToSql()
+	assert.NoError(t, err)
+	expectedSql := "SELECT id FROM users WHERE " +
+		"((name > $1 OR name IS NULL) OR (name = $2 AND age < $3) OR (name = $4 AND age = $5 AND id > $6)) " +
+		"ORDER BY name ASC NULLS LAST, age DESC NULLS FIRST, id ASC"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"moe", "moe", 42, "moe", 42, 7}, args)
+
+	sql, args, err = Select("id").From("users").Seek(columns, nil, nil, 7).ToSql()
+	assert.NoError(t, err)
+	expectedSql = "SELECT id FROM users WHERE " +
+		"((name IS NULL AND age IS NOT NULL) OR (name IS NULL AND age IS NULL AND id > ?)) " +
+		"ORDER BY name ASC NULLS LAST, age DESC NULLS FIRST, id ASC"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{7}, args)
+
+	sql, args, err = Select("id").From("users").Seek([]SortColumn{Asc("name").NullsLast()}, nil).Dialect(SQLite).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE 0 ORDER BY name ASC NULLS LAST", sql)
+	assert.Empty(t, args)
+}
+
+func TestSortColumnNullsEmulated(t *testing.T) {
+	b := Select("id").From("users").OrderBySort(Desc("name").NullsLast(), Asc("age").NullsFirst())
+
+	sql, _, err := b.Dialect(MySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY "+
+		"CASE WHEN name IS NULL THEN 1 ELSE 0 END, name DESC, "+
+		"CASE WHEN age IS NULL THEN 0 ELSE 1 END, age ASC", sql)
+
+	sql, _, err = b.Dialect(Oracle).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY name DESC NULLS LAST, age ASC NULLS FIRST", sql)
+}
+
+func TestSeekErrors(t *testing.T) {
+	_, _, err := Select("id").From("users").Seek([]SortColumn{Asc("name"), Asc("id")}, "moe").ToSql()
+	assert.EqualError(t, err, "keyset cursor has 1 values for 2 sort columns")
+
+	_, _, err = Select("id").From("users").Seek([]SortColumn{Asc("id")}, nil).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("id").From("users").Seek(nil, 42).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("id").From("users").Seek([]SortColumn{Asc("")}).ToSql()
+	assert.Error(t, err)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	token, err := EncodeCursor(int32(-1), uint(2), 1.5, "moe", true, []byte{0, 1}, ts, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, token, "=")
+
+	values, err := DecodeCursor(token)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(-1), uint64(2), 1.5, "moe", true, []byte{0, 1}, ts, nil}, values)
+}
+
+func TestEncodeCursorErr(t *testing.T) {
+	_, err := EncodeCursor(struct{}{})
+	assert.Error(t, err)
+}
+
+func TestDecodeCursorErr(t *testing.T) {
+	for _, token := range []string{"!", "bm90IGpzb24", "W3sidCI6InoifV0"} {
+		_, err := DecodeCursor(token)
+		assert.True(t, errors.Is(err, InvalidCursor), "token %q: %v", token, err)
+	}
+}
@@ -0,0 +1,345 @@
+package squirrel
+
+import (
+	"bytes"
+	"strings"
+)
+
+// exprKeywords are the SQL keywords that can be followed by an expression, and
+// so by a ? placeholder rather than a ? operator.
+var exprKeywords = map[string]bool{
+	"AGAINST": true, "ALL": true, "AND": true, "ANY": true, "ARRAY": true,
+	"AS": true, "AT": true, "BETWEEN": true, "BINARY": true, "BY": true,
+	"CASE": true, "DEFAULT": true, "DISTINCT": true, "DIV": true, "DO": true,
+	"ELSE": true, "ESCAPE": true, "EXCEPT": true, "EXISTS": true,
+	"FETCH": true, "FIRST": true, "FROM": true, "GLOB": true, "HAVING": true,
+	"ILIKE": true, "IN": true, "INTERSECT": true, "INTERVAL": true, "IS": true,
+	"LIKE": true, "LIMIT": true, "MATCH": true, "MOD": true, "NEXT": true,
+	"NOT": true, "OFFSET": true, "ON": true, "OR": true, "REGEXP": true,
+	"RETURN": true, "RETURNING": true, "RLIKE": true, "SELECT": true,
+	"SET": true, "SIMILAR": true, "SOME": true, "THEN": true, "TO": true,
+	"TOP": true, "UNION": true, "USING": true, "VALUES": true, "WHEN": true,
+	"WHERE": true, "WITH": true, "XOR": true, "ZONE": true,
+}
+
+// backslashMode is whether backslashes escape quotes in string literals.
+type backslashMode int
+
+const (
+	// as for backslashNever, unless that leaves a string literal
+	// unterminated, as with MySQL's 'it\'s'
+	backslashAuto backslashMode = iota
+	// only in PostgreSQL's E'...' strings, as in standard SQL
+	backslashNever
+	// in all '...' and "..." strings, as in MySQL
+	backslashAlways
+)
+
+// backslashesOf returns the backslashMode of the string literals of d.
+func backslashesOf(d Dialect) backslashMode {
+	if d != nil && d.literals() == literalsMySQL {
+		return backslashAlways
+	}
+	return backslashAuto
+}
+
+// rewritePlaceholders copies sql, calling write in place of each of its
+// placeholders with its 0-based index, and returns the number of placeholders.
+//
+// Without a prefix, placeholders are question marks and ?? is an escaped ?.
+// The ?, ?| and ?& operators of PostgreSQL's jsonb are recognized as such
+// when they follow an operand and are either ?| or ?&, or followed by a string
+// literal, an array or a placeholder, as in data ? 'key' or
+// tags ?| array[?, ?]. With a prefix, placeholders are the prefix followed by
+// their 1-based number, e.g. $1 or :1.
+//
+// String literals, quoted identifiers, PostgreSQL's dollar-quoted strings and
+// comments are copied as they are, except for the ?? escapes of ? placeholders,
+// which are unescaped there too. Backslashes escape quotes in string literals
+// as set by backslashes.
+func rewritePlaceholders(sql, prefix string, backslashes backslashMode, write func(buf *bytes.Buffer, i int) error) (string, int, error) {
+	return lexPlaceholders(sql, prefix, false, backslashes, func(buf *bytes.Buffer, i int, _ string) error {
+		return write(buf, i)
+	})
+}
+
+// rewriteParams is rewritePlaceholders, also calling write with the name of
+// each of the @name parameters of sql, and -1 for index, when prefix starts
+// with @, as do the placeholders of SQL Server. The number returned is that of
+// the numbered placeholders only.
+func rewriteParams(sql, prefix string, backslashes backslashMode, write func(buf *bytes.Buffer, i int, name string) error) (string, int, error) {
+	n := 0
+	sql, _, err := lexPlaceholders(sql, prefix, strings.HasPrefix(prefix, "@"), backslashes, func(buf *bytes.Buffer, i int, param string) error {
+		if len(param) > 0 {
+			return write(buf, -1, param[1:])
+		}
+		n++
+		return write(buf, i, "")
+	})
+	return sql, n, err
+}
+
+// rewriteNamed copies sql, calling write in place of each of its :name and
+// @name parameters, and returns the number of parameters and of ? placeholders,
+// which are copied as they are, as are ?? escapes.
+//
+// Casts such as x::int, MySQL's @@system variables and := assignments are not
+// parameters, nor are names following a letter or digit, as in arr[1:n].
+func rewriteNamed(sql string, write func(buf *bytes.Buffer, name string) error) (string, int, int, error) {
+	return rewriteNamedParams(sql, func(buf *bytes.Buffer, param string) error {
+		if len(param) == 0 {
+			buf.WriteByte('?')
+			return nil
+		}
+		return write(buf, param[1:])
+	})
+}
+
+// rewriteNamedParams is rewriteNamed, also calling write with "" in place of
+// each ? placeholder, and with the parameters as written, e.g. ":name".
+func rewriteNamedParams(sql string, write func(buf *bytes.Buffer, param string) error) (string, int, int, error) {
+	positional := 0
+	sql, count, err := lexPlaceholders(sql, "", true, backslashAuto, func(buf *bytes.Buffer, _ int, param string) error {
+		if len(param) == 0 {
+			positional++
+		}
+		return write(buf, param)
+	})
+	return sql, count - positional, positional, err
+}
+
+// unterminatedString reports whether sql has an unterminated string literal
+// or quoted identifier when backslashes escape quotes as set by backslash.
+func unterminatedString(sql string, backslash bool) bool {
+	_, _, unterminated, _ := lexSql(sql, "", false, backslash, func(*bytes.Buffer, int, string) error {
+		return nil
+	})
+	return unterminated
+}
+
+func lexPlaceholders(sql, prefix string, named bool, backslashes backslashMode, write func(buf *bytes.Buffer, i int, name string) error) (string, int, error) {
+	backslash := backslashes == backslashAlways ||
+		backslashes == backslashAuto && unterminatedString(sql, false) && !unterminatedString(sql, true)
+	sql, count, _, err := lexSql(sql, prefix, named, backslash, write)
+	return sql, count, err
+}
+
+// lexSql is lexPlaceholders with backslash escapes in all string literals
+// when backslash is set, also reporting whether sql has an unterminated
+// string literal or quoted identifier.
+func lexSql(sql, prefix string, named, backslash bool, write func(buf *bytes.Buffer, i int, name string) error) (string, int, bool, error) {
+	buf := &bytes.Buffer{}
+	count := 0
+	unterminated := false
+	// whether the last token is an operand, which a ? operator would follow
+	operand := false
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		j := i + 1
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// PostgreSQL's E'...' strings escape quotes with backslashes.
+			escapes := backslash && c != '`' ||
+				c == '\'' && i > 0 && (sql[i-1] == 'E' || sql[i-1] == 'e') && (i < 2 || !isWordChar(sql[i-2]))
+			var ok bool
+			if j, ok = skipQuoted(sql, i, c, escapes); !ok {
+				unterminated = true
+			}
+			operand = true
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			if j = strings.IndexByte(sql[i:], '\n'); j < 0 {
+				j = len(sql)
+			} else {
+				j += i
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			j = skipBlockComment(sql, i)
+		case c == '$' && (i == 0 || !isWordChar(sql[i-1])) && dollarTag(sql[i:]) != "":
+			tag := dollarTag(sql[i:])
+			if end := strings.Index(sql[i+len(tag):], tag); end < 0 {
+				j = len(sql)
+			} else {
+				j = i + len(tag) + end + len(tag)
+			}
+			operand = true
+		case len(prefix) == 0 && c == '?':
+			switch {
+			case strings.HasPrefix(sql[i:], "??"):
+				// escape ?? => ?, left for the PlaceholderFormat when named
+				operand = false
+				if !named {
+					buf.WriteByte('?')
+					i += 2
+					continue
+				}
+				j = i + 2
+			case operand && isJsonbOperator(sql, i):
+				if sql[j] == '|' || sql[j] == '&' {
+					j++
+				}
+				operand = false
+			default:
+				if err := write(buf, count, ""); err != nil {
+					return "", count, unterminated, err
+				}
+				count++
+				i = j
+				operand = true
+				continue
+			}
+		case (len(prefix) > 0 || named) && c == ':' && strings.HasPrefix(sql[i:], "::"):
+			// cast
+			j = i + 2
+			operand = false
+		case named && c == '@' && strings.HasPrefix(sql[i:], "@@"):
+			// MySQL system variable
+			for j = i + 2; j < len(sql) && (isWordChar(sql[j]) || sql[j] == '.'); j++ {
+			}
+			operand = true
+		case len(prefix) > 0 && strings.HasPrefix(sql[i:], prefix) && isDigits(sql, i+len(prefix)):
+			j = i + len(prefix)
+			n := 0
+			for ; j < len(sql) && isDigit(sql[j]); j++ {
+				n = n*10 + int(sql[j]-'0')
+			}
+			if err := write(buf, n-1, ""); err != nil {
+				return "", count, unterminated, err
+			}
+			count++
+			i = j
+			operand = true
+			continue
+		case named && (c == '@' || c == ':' && len(prefix) == 0) && (i == 0 || !isWordChar(sql[i-1])) && isNameStart(sql, j):
+			for j < len(sql) && isIdentChar(sql[j]) {
+				j++
+			}
+			if err := write(buf, count, sql[i:j]); err != nil {
+				return "", count, unterminated, err
+			}
+			count++
+			i = j
+			operand = true
+			continue
+		case isWordChar(c):
+			for j < len(sql) && isWordChar(sql[j]) {
+				j++
+			}
+			operand = isDigit(c) || !exprKeywords[strings.ToUpper(sql[i:j])]
+		case c == ')' || c == ']':
+			operand = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			operand = false
+		}
+		if len(prefix) == 0 && !named {
+			// Literals and comments are still unescaped, as they always were.
+			buf.WriteString(strings.Replace(sql[i:j], "??", "?", -1))
+		} else {
+			buf.WriteString(sql[i:j])
+		}
+		i = j
+	}
+	return buf.String(), count, unterminated, nil
+}
+
+// isJsonbOperator reports whether the ? at i of sql, following an operand, is
+// a ?, ?| or ?& jsonb operator rather than a placeholder: when it is ?| or ?&,
+// or is followed by a string literal, an array or a ? placeholder.
+func isJsonbOperator(sql string, i int) bool {
+	j := i + 1
+	if j < len(sql) && (sql[j] == '|' || sql[j] == '&') {
+		// not a placeholder followed by || or &&
+		return j+1 >= len(sql) || sql[j+1] != sql[j]
+	}
+	for j < len(sql) && (sql[j] == ' ' || sql[j] == '\t' || sql[j] == '\n' || sql[j] == '\r') {
+		j++
+	}
+	rest := sql[j:]
+	switch {
+	case strings.HasPrefix(rest, "'"):
+		return true
+	case len(rest) > 1 && (rest[0] == 'E' || rest[0] == 'e') && rest[1] == '\'':
+		return true
+	case len(rest) >= 6 && strings.EqualFold(rest[:6], "ARRAY["):
+		return true
+	case strings.HasPrefix(rest, "?"):
+		return !strings.HasPrefix(rest, "??")
+	}
+	return false
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// isNameStart reports whether sql has a letter or an underscore at i.
+func isNameStart(sql string, i int) bool {
+	return i < len(sql) && isIdentChar(sql[i]) && !isDigit(sql[i])
+}
+
+// isDigits reports whether sql has a digit at i.
+func isDigits(sql string, i int) bool {
+	return i < len(sql) && isDigit(sql[i])
+}
+
+// isWordChar reports whether c can be part of an identifier, keyword or
+// number. Non-ASCII bytes are taken for letters.
+func isWordChar(c byte) bool {
+	return isIdentChar(c) || c == '$' || c >= 0x80
+}
+
+// skipQuoted returns the index following the quoted string or identifier
+// starting at i, where doubled quotes are escaped quotes, and whether it is
+// terminated.
+func skipQuoted(sql string, i int, quote byte, backslash bool) (int, bool) {
+	for j := i + 1; j < len(sql); j++ {
+		switch sql[j] {
+		case '\\':
+			if backslash {
+				j++
+			}
+		case quote:
+			if j+1 < len(sql) && sql[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1, true
+		}
+	}
+	return len(sql), false
+}
+
+// skipBlockComment returns the index following the possibly nested block
+// comment starting at i.
+func skipBlockComment(sql string, i int) int {
+	depth := 0
+	for j := i; j < len(sql)-1; j++ {
+		switch sql[j : j+2] {
+		case "/*":
+			depth++
+			j++
+		case "*/":
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(sql)
+}
+
+// dollarTag returns the $tag$ starting a dollar-quoted string at the start of
+// s, or "" if there is none.
+func dollarTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		switch c := s[j]; {
+		case c == '$':
+			return s[:j+1]
+		case isDigit(c) && j == 1, !isIdentChar(c):
+			return ""
+		}
+	}
+	return ""
+}
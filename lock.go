@@ -0,0 +1,58 @@
+package squirrel
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Row locking modes and wait policies of SelectBuilder.
+const (
+	lockUpdate     = "UPDATE"
+	lockShare      = "SHARE"
+	lockNoWait     = "NOWAIT"
+	lockSkipLocked = "SKIP LOCKED"
+)
+
+// appendLockToSql writes the row locking clause of a query, including a
+// leading space, to w as rendered for d.
+func appendLockToSql(w io.Writer, d Dialect, mode string, of []string, wait string) error {
+	if len(mode) == 0 {
+		if len(of) > 0 || len(wait) > 0 {
+			return fmt.Errorf("row locking options need ForUpdate or ForShare")
+		}
+		return nil
+	}
+
+	d = dialectOr(d)
+	switch d.locking() {
+	case lockingNone:
+		return fmt.Errorf("%s dialect does not support row locking clauses", d.Name())
+	case lockingUpdateOnly:
+		if mode != lockUpdate {
+			return fmt.Errorf("%s dialect does not support FOR %s", d.Name(), mode)
+		}
+	case lockingMySQL:
+		if mode == lockShare && len(of) == 0 && len(wait) == 0 {
+			// FOR SHARE only exists since MySQL 8.0, as do OF and the wait
+			// policies; LOCK IN SHARE MODE works with every version.
+			_, err := io.WriteString(w, " LOCK IN SHARE MODE")
+			return err
+		}
+	}
+
+	sql := &bytes.Buffer{}
+	sql.WriteString(" FOR ")
+	sql.WriteString(mode)
+	if len(of) > 0 {
+		sql.WriteString(" OF ")
+		sql.WriteString(strings.Join(of, ", "))
+	}
+	if len(wait) > 0 {
+		sql.WriteString(" ")
+		sql.WriteString(wait)
+	}
+	_, err := io.WriteString(w, sql.String())
+	return err
+}
@@ -0,0 +1,75 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectForUpdate(t *testing.T) {
+	b := Select("id").From("jobs").Where("state = ?", "queued").OrderBy("id").Limit(10)
+
+	sql, _, err := b.ForUpdate().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM jobs WHERE state = ? ORDER BY id LIMIT 10 FOR UPDATE", sql)
+
+	sql, _, err = b.ForUpdate().LockOf("jobs").SkipLocked().Dialect(PostgreSQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM jobs WHERE state = $1 ORDER BY id LIMIT 10 FOR UPDATE OF jobs SKIP LOCKED", sql)
+
+	sql, _, err = b.ForUpdate().NoWait().Suffix("/* poll */").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM jobs WHERE state = ? ORDER BY id LIMIT 10 FOR UPDATE NOWAIT /* poll */", sql)
+
+	sql, _, err = b.ForUpdate().RemoveLock().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM jobs WHERE state = ? ORDER BY id LIMIT 10", sql)
+}
+
+func TestSelectForShare(t *testing.T) {
+	b := Select("id").From("accounts").ForShare()
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM accounts FOR SHARE", sql)
+
+	sql, _, err = b.Dialect(MySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM accounts LOCK IN SHARE MODE", sql)
+
+	sql, _, err = b.NoWait().Dialect(MySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM accounts FOR SHARE NOWAIT", sql)
+}
+
+func TestSelectLockDialects(t *testing.T) {
+	b := Select("id").From("jobs").OrderBy("id").Limit(1).ForUpdate().SkipLocked()
+
+	sql, _, err := b.RemoveLimit().Dialect(Oracle).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM jobs ORDER BY id FOR UPDATE SKIP LOCKED", sql)
+
+	// Oracle rejects FOR UPDATE after OFFSET and FETCH.
+	_, _, err = b.Dialect(Oracle).ToSql()
+	assert.EqualError(t, err, "rows of a query paginated with OFFSET or FETCH cannot be locked")
+
+	_, _, err = b.RemoveLimit().Offset(5).Dialect(Oracle).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = b.ForShare().Dialect(Oracle).ToSql()
+	assert.EqualError(t, err, "oracle dialect does not support FOR SHARE")
+
+	_, _, err = b.Dialect(Oracle11g).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = b.Dialect(SQLite).ToSql()
+	assert.EqualError(t, err, "sqlite3 dialect does not support row locking clauses")
+
+	_, _, err = b.Dialect(SQLServer).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectLockOptionsWithoutMode(t *testing.T) {
+	_, _, err := Select("id").From("jobs").SkipLocked().ToSql()
+	assert.Error(t, err)
+}
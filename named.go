@@ -0,0 +1,201 @@
+package squirrel
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+// namedArgs returns the function looking up the named parameters bound by
+// args, or nil if args are positional.
+//
+// Parameters are bound by name with a single string-keyed map, a single
+// struct or pointer to one whose fields are mapped to names by their db tags
+// as with InsertBuilder.SetStruct, or sql.NamedArg arguments only, e.g.
+//   Expr("status = :status OR owner = :status", map[string]interface{}{"status": "open"})
+//   Where("created_at > @since", sql.Named("since", t))
+func namedArgs(args []interface{}) func(name string) (interface{}, bool) {
+	if len(args) == 0 {
+		return nil
+	}
+
+	if _, ok := args[0].(sql.NamedArg); ok {
+		values := make(map[string]interface{}, len(args))
+		for _, arg := range args {
+			named, ok := arg.(sql.NamedArg)
+			if !ok {
+				return nil
+			}
+			values[named.Name] = named.Value
+		}
+		return func(name string) (interface{}, bool) {
+			value, ok := values[name]
+			return value, ok
+		}
+	}
+
+	if len(args) > 1 {
+		return nil
+	}
+	switch args[0].(type) {
+	case Sqlizer, driver.Valuer:
+		return nil
+	}
+	v := reflect.ValueOf(args[0])
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		return func(name string) (interface{}, bool) {
+			value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !value.IsValid() {
+				return nil, false
+			}
+			return value.Interface(), true
+		}
+	case v.Kind() == reflect.Struct && !isScalarStruct(v.Type()):
+		return func(name string) (interface{}, bool) {
+			fields, err := structFields(v.Type())
+			if err != nil {
+				return nil, false
+			}
+			for _, f := range fields {
+				if f.column == name {
+					if value, ok := fieldValue(v, f.index); ok {
+						return value.Interface(), true
+					}
+					return nil, true
+				}
+			}
+			return nil, false
+		}
+	}
+	return nil
+}
+
+// namedParam is the argument of an @name parameter bound by a sql.NamedArg
+// and passed through by bindNamedDialect, which finalizePlaceholders turns
+// back into a sql.NamedArg.
+type namedParam struct {
+	name  string
+	value interface{}
+}
+
+// countNamedParams returns the number of namedParam values of args.
+func countNamedParams(args []interface{}) int {
+	n := 0
+	for _, arg := range args {
+		if _, ok := arg.(namedParam); ok {
+			n++
+		}
+	}
+	return n
+}
+
+// bindNamed rewrites the :name and @name parameters of sql to ? placeholders
+// and returns their values as positional arguments, when args binds
+// parameters by name. Parameters are bound once per occurrence, and slices
+// other than []byte are expanded to a placeholder per element, as for Eq.
+//
+// Positional args are returned as they are, and so are args that could bind
+// parameters by name when sql has none, or has ? placeholders, as with a map
+// bound to a single json or hstore placeholder.
+func bindNamed(sql string, args []interface{}) (string, []interface{}, error) {
+	return bindNamedDialect(nil, sql, args)
+}
+
+// bindNamedDialect is bindNamed for a statement of d. When the
+// PlaceholderFormat of d keeps @name parameters, those bound by sql.NamedArg
+// arguments are written as @name and bound to a namedParam once, except for
+// slices.
+func bindNamedDialect(d Dialect, query string, args []interface{}) (string, []interface{}, error) {
+	lookup := namedArgs(args)
+	if lookup == nil {
+		return query, args, nil
+	}
+	_, names, positional, _ := rewriteNamed(query, func(*bytes.Buffer, string) error { return nil })
+	if names == 0 || positional > 0 {
+		return query, args, nil
+	}
+
+	_, keep := args[0].(sql.NamedArg)
+	keep = keep && keepsNamedArgs(dialectOr(d).PlaceholderFormat())
+	kept := make(map[string]bool)
+	var bound []interface{}
+	query, _, _, err := rewriteNamed(query, func(buf *bytes.Buffer, name string) error {
+		value, ok := lookup(name)
+		if !ok {
+			return fmt.Errorf("named parameter %q is not bound", name)
+		}
+		if _, valuer := value.(driver.Valuer); isListType(value) && !valuer {
+			v := reflect.ValueOf(value)
+			if v.Len() == 0 {
+				return fmt.Errorf("named parameter %q is bound to an empty %T", name, value)
+			}
+			for i := 0; i < v.Len(); i++ {
+				bound = append(bound, v.Index(i).Interface())
+			}
+			buf.WriteString(Placeholders(v.Len()))
+			return nil
+		}
+		if keep {
+			if !kept[name] {
+				kept[name] = true
+				bound = append(bound, namedParam{name: name, value: value})
+			}
+			buf.WriteString("@" + name)
+			return nil
+		}
+		bound = append(bound, value)
+		buf.WriteByte('?')
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return query, bound, nil
+}
+
+// unbindNamedParams rewrites the @name parameters of query, a statement with
+// args, that are bound to a namedParam to ? placeholders, and returns all
+// arguments in the order of their placeholders, for PlaceholderFormats that do
+// not keep them.
+func unbindNamedParams(query string, args []interface{}) (string, []interface{}, error) {
+	values := make(map[string]interface{})
+	var positional []interface{}
+	for _, arg := range args {
+		if p, ok := arg.(namedParam); ok {
+			values[p.name] = p.value
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+
+	var bound []interface{}
+	query, _, _, err := rewriteNamedParams(query, func(buf *bytes.Buffer, param string) error {
+		if len(param) == 0 {
+			if len(positional) == 0 {
+				return fmt.Errorf("too many placeholders in %q", query)
+			}
+			bound = append(bound, positional[0])
+			positional = positional[1:]
+			buf.WriteByte('?')
+			return nil
+		}
+		value, ok := values[param[1:]]
+		if !ok || param[0] != '@' {
+			buf.WriteString(param)
+			return nil
+		}
+		bound = append(bound, value)
+		buf.WriteByte('?')
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return query, append(bound, positional...), nil
+}
@@ -0,0 +1,179 @@
+package squirrel
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExprNamedMap(t *testing.T) {
+	sqlStr, args, err := Expr(
+		"status = :status AND (owner = :user OR assignee = :user) AND id IN (:ids)",
+		map[string]interface{}{"status": "open", "user": 42, "ids": []int{1, 2, 3}},
+	).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "status = ? AND (owner = ? OR assignee = ?) AND id IN (?,?,?)", sqlStr)
+	assert.Equal(t, []interface{}{"open", 42, 42, 1, 2, 3}, args)
+}
+
+func TestExprNamedStruct(t *testing.T) {
+	type filter struct {
+		Status  string `db:"status"`
+		Owner   int64  `db:"owner_id"`
+		Data    []byte `db:"data"`
+		Ignored string
+	}
+	f := filter{Status: "open", Owner: 7, Data: []byte("x")}
+
+	sqlStr, args, err := Expr("status = @status AND owner_id = @owner_id AND data = @data", &f).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "status = ? AND owner_id = ? AND data = ?", sqlStr)
+	assert.Equal(t, []interface{}{"open", int64(7), []byte("x")}, args)
+
+	_, _, err = Expr("x = :Ignored", f).ToSql()
+	assert.EqualError(t, err, `named parameter "Ignored" is not bound`)
+}
+
+func TestExprNamedArgs(t *testing.T) {
+	sqlStr, args, err := Expr("a = @a OR b = :b OR a > @a", sql.Named("a", 1), sql.Named("b", "x")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a = ? OR b = ? OR a > ?", sqlStr)
+	assert.Equal(t, []interface{}{1, "x", 1}, args)
+}
+
+func TestExprNamedSkipped(t *testing.T) {
+	params := map[string]interface{}{"id": 1}
+	sqlStr, args, err := Expr(
+		"x::int = :id AND y = ':id' AND @@session.v = 1 AND z = \"@id\" AND arr[1:id] = 0 -- :id\nAND data ? 'k' AND data ?? 'k'",
+		params,
+	).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"x::int = ? AND y = ':id' AND @@session.v = 1 AND z = \"@id\" AND arr[1:id] = 0 -- :id\nAND data ? 'k' AND data ?? 'k'",
+		sqlStr)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestExprNamedErrors(t *testing.T) {
+	_, _, err := Expr("a = :b", map[string]interface{}{"a": 1}).ToSql()
+	assert.EqualError(t, err, `named parameter "b" is not bound`)
+
+	_, _, err = Expr("a IN (:a)", map[string]interface{}{"a": []int{}}).ToSql()
+	assert.EqualError(t, err, `named parameter "a" is bound to an empty []int`)
+}
+
+func TestExprPositionalUnchanged(t *testing.T) {
+	sqlStr, args, err := Expr("a = ? AND b = :b", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a = ? AND b = :b", sqlStr)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sqlStr, args, err = Expr("a = ?", sql.NullInt64{Int64: 1, Valid: true}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a = ?", sqlStr)
+	assert.Equal(t, []interface{}{sql.NullInt64{Int64: 1, Valid: true}}, args)
+
+	params := map[string]interface{}{"a": 1}
+	sqlStr, args, err = Expr("a = :a AND b = ?", params).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a = :a AND b = ?", sqlStr)
+	assert.Equal(t, []interface{}{params}, args)
+}
+
+func TestWhereSingleMapOrStructArg(t *testing.T) {
+	attrs := map[string]interface{}{"color": "red"}
+	sqlStr, args, err := Select("id").From("items").
+		Where("attrs @> ?", attrs).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM items WHERE attrs @> $1", sqlStr)
+	assert.Equal(t, []interface{}{attrs}, args)
+
+	type point struct {
+		X int `db:"x"`
+		Y int `db:"y"`
+	}
+	p := point{X: 1, Y: 2}
+	sqlStr, args, err = Select("id").From("items").Where("pos = ?", p).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM items WHERE pos = ?", sqlStr)
+	assert.Equal(t, []interface{}{p}, args)
+
+	sqlStr, args, err = Select("id").From("items").Where("kind = 'x'", attrs).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM items WHERE kind = 'x'", sqlStr)
+	assert.Equal(t, []interface{}{attrs}, args)
+}
+
+func TestSelectBuilderNamed(t *testing.T) {
+	params := map[string]interface{}{"status": "open", "ids": []int{1, 2}}
+	sqlStr, args, err := Select("id").
+		Prefix("WITH x AS (SELECT :status AS s)", params).
+		From("tickets").
+		Where("status = :status OR id IN (:ids)", params).
+		Where("priority > ?", 3).
+		Where(Expr("owner = @owner", sql.Named("owner", "me"))).
+		Suffix("LIMIT :n", map[string]interface{}{"n": 10}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	expectedSql := "WITH x AS (SELECT $1 AS s) SELECT id FROM tickets " +
+		"WHERE status = $2 OR id IN ($3,$4) AND priority > $5 AND owner = $6 LIMIT $7"
+	assert.Equal(t, expectedSql, sqlStr)
+	assert.Equal(t, []interface{}{"open", "open", 1, 2, 3, "me", 10}, args)
+
+	_, _, err = Select("id").From("t").Suffix("LIMIT :n", map[string]interface{}{}).ToSql()
+	assert.EqualError(t, err, `named parameter "n" is not bound`)
+}
+
+func TestUpdateBuilderNamed(t *testing.T) {
+	sqlStr, args, err := Update("t").
+		Set("a", Expr("a + :delta", map[string]interface{}{"delta": 2})).
+		Where("id = :id", map[string]interface{}{"id": 1}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = a + ? WHERE id = ?", sqlStr)
+	assert.Equal(t, []interface{}{2, 1}, args)
+}
+
+func TestWhereNamedArgsPassThrough(t *testing.T) {
+	b := Select("id").From("t").Where("id = @id", sql.Named("id", 1)).Dialect(SQLServer)
+
+	sqlStr, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE id = @id", sqlStr)
+	assert.Equal(t, []interface{}{sql.Named("id", 1)}, args)
+
+	sqlStr, args, err = b.Where("a = ?", 5).
+		Where("id = :id OR parent = @id OR kind IN (@kinds)", sql.Named("id", 1), sql.Named("kinds", []int{2, 3})).
+		Where(Expr("b = @b", sql.Named("b", "x"))).
+		Where(Eq{"c": 4}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT id FROM t WHERE id = @id AND a = @p2 AND id = @id OR parent = @id OR kind IN (@p3,@p4) AND b = @b AND c = @p6",
+		sqlStr)
+	assert.Equal(t, []interface{}{sql.Named("id", 1), 5, 2, 3, sql.Named("b", "x"), 4}, args)
+
+	_, _, err = b.Where("parent = @id", sql.Named("id", 2)).ToSql()
+	assert.EqualError(t, err, `named parameter "id" is bound to different values`)
+
+	// Formats without @name parameters get them back as placeholders.
+	sqlStr, args, err = b.Where("a = ?", 5).PlaceholderFormat(Question).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE id = ? AND a = ?", sqlStr)
+	assert.Equal(t, []interface{}{1, 5}, args)
+
+	sqlStr, args, err = b.Where("a = ?", 5).PlaceholderFormat(NamedArgs("@p")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE id = @id AND a = @p2", sqlStr)
+	assert.Equal(t, []interface{}{sql.Named("id", 1), sql.Named("p2", 5)}, args)
+
+	// Maps and structs are still bound to placeholders.
+	sqlStr, args, err = Select("id").From("t").Where("id = @id", map[string]interface{}{"id": 1}).Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE id = @p1", sqlStr)
+	assert.Equal(t, []interface{}{1}, args)
+}
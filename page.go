@@ -0,0 +1,213 @@
+package squirrel
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// PageCount is the way QueryPage counts the total rows of a paginated query.
+type PageCount int
+
+const (
+	// CountSeparately counts the total rows with the query's CountQuery. When
+	// the Runner is a *sql.DB, both statements run in one transaction.
+	CountSeparately PageCount = iota
+
+	// CountOver counts the total rows with a COUNT(*) OVER () column added to
+	// the page query, saving a statement on databases with window functions.
+	// The count query still runs when the page is empty.
+	CountOver
+)
+
+// PageResult describes the page of rows returned by QueryPage.
+type PageResult struct {
+	// Total is the number of rows of the query without its pagination.
+	Total uint64
+	// Page is the 1-based number of the page.
+	Page uint64
+	// PerPage is the maximum number of rows of a page, i.e. the query's limit.
+	PerPage uint64
+	// Rows is the number of rows of the page.
+	Rows uint64
+	// HasNext reports whether there are rows after the page.
+	HasNext bool
+}
+
+// pageRows are the rows of a page, as returned by *sql.Rows.
+type pageRows interface {
+	RowScanner
+	Next() bool
+	Err() error
+	Close() error
+}
+
+// pageRunner runs the statements of QueryPage.
+type pageRunner struct {
+	query    func(Sqlizer) (pageRows, error)
+	queryRow func(Sqlizer) RowScanner
+}
+
+type txBeginner interface {
+	Begin() (*sql.Tx, error)
+}
+
+// runnerDB returns the database/sql value wrapped by RunWith, if any.
+func runnerDB(runner BaseRunner) interface{} {
+	if r, ok := runner.(*stdsqlRunner); ok {
+		return r.stdsql
+	}
+	return runner
+}
+
+// countOverScanner scans the COUNT(*) OVER () column of a row into total,
+// after the columns of the query.
+type countOverScanner struct {
+	RowScanner
+	total *uint64
+}
+
+func (s countOverScanner) Scan(dest ...interface{}) error {
+	withTotal := make([]interface{}, len(dest), len(dest)+1)
+	copy(withTotal, dest)
+	return s.RowScanner.Scan(append(withTotal, s.total)...)
+}
+
+// QueryPage builds and Querys the page of rows selected by the query's Limit
+// and Offset (or LimitRowNum and Page) with the Runner set by RunWith, calling
+// scan for each row, and counts the rows of the query without its pagination.
+//
+// scan must scan exactly the columns of the query; with CountOver the total
+// column is scanned for it.
+//
+// The total is derived from the page alone, without counting, when the page
+// is not full and either not empty or the first page.
+func (b SelectBuilder) QueryPage(count PageCount, scan func(RowScanner) error) (PageResult, error) {
+	data := builder.GetStruct(b).(selectData)
+	if data.RunWith == nil {
+		return PageResult{}, RunnerNotSet
+	}
+	runner, ok := data.RunWith.(Runner)
+	if !ok {
+		return PageResult{}, RunnerNotQueryRunner
+	}
+
+	if db, ok := runnerDB(runner).(txBeginner); ok && count == CountSeparately {
+		tx, err := db.Begin()
+		if err != nil {
+			return PageResult{}, err
+		}
+		defer tx.Rollback()
+		runner = &stdsqlRunner{tx}
+
+		result, err := b.queryPage(newPageRunner(runner), count, scan)
+		if err != nil {
+			return result, err
+		}
+		return result, tx.Commit()
+	}
+	return b.queryPage(newPageRunner(runner), count, scan)
+}
+
+func newPageRunner(runner Runner) pageRunner {
+	return pageRunner{
+		query: func(s Sqlizer) (pageRows, error) {
+			rows, err := QueryWith(runner, s)
+			if err != nil {
+				return nil, err
+			}
+			return rows, nil
+		},
+		queryRow: func(s Sqlizer) RowScanner {
+			return QueryRowWith(runner, s)
+		},
+	}
+}
+
+func (b SelectBuilder) queryPage(r pageRunner, count PageCount, scan func(RowScanner) error) (result PageResult, err error) {
+	data := builder.GetStruct(b).(selectData)
+
+	limitStr, offsetStr, err := data.limitOffset()
+	if err != nil {
+		return
+	}
+	if len(limitStr) == 0 {
+		err = fmt.Errorf("QueryPage requires the query to have a Limit or LimitRowNum")
+		return
+	}
+	limit, err := strconv.ParseUint(limitStr, 10, 64)
+	if err != nil || limit == 0 {
+		err = fmt.Errorf("QueryPage requires a Limit greater than zero, not %s", limitStr)
+		return
+	}
+	var offset uint64
+	if len(offsetStr) > 0 {
+		if offset, err = strconv.ParseUint(offsetStr, 10, 64); err != nil {
+			err = fmt.Errorf("invalid offset %q: %v", offsetStr, err)
+			return
+		}
+	}
+
+	pageQuery := b
+	if count == CountOver {
+		for _, option := range data.Options {
+			if strings.EqualFold(option, "DISTINCT") {
+				// The window is computed before DISTINCT is applied.
+				err = fmt.Errorf("CountOver cannot count the rows of a DISTINCT query; use CountSeparately")
+				return
+			}
+		}
+		if data.pagination() == paginationRowNumber {
+			err = fmt.Errorf("CountOver cannot be combined with ROW_NUMBER pagination; use CountSeparately")
+			return
+		}
+		pageQuery = b.Column(Expr("COUNT(*) OVER ()"))
+	}
+
+	rows, err := r.query(pageQuery)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var total, n uint64
+	var scanner RowScanner = rows
+	if count == CountOver {
+		scanner = countOverScanner{RowScanner: rows, total: &total}
+	}
+	for rows.Next() {
+		if err = scan(scanner); err != nil {
+			return
+		}
+		n++
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+	if err = rows.Close(); err != nil {
+		return
+	}
+
+	counted := count == CountOver && n > 0
+	if !counted && n < limit && (n > 0 || offset == 0) {
+		total = offset + n
+		counted = true
+	}
+	if !counted {
+		if err = r.queryRow(b.CountQuery()).Scan(&total); err != nil {
+			return
+		}
+	}
+
+	result = PageResult{
+		Total:   total,
+		Page:    offset/limit + 1,
+		PerPage: limit,
+		Rows:    n,
+		HasNext: offset+n < total,
+	}
+	return
+}
@@ -0,0 +1,66 @@
+//go:build go1.8
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lann/builder"
+)
+
+type txBeginnerContext interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+type pageRunnerContext interface {
+	QueryerContext
+	QueryRowerContext
+}
+
+// QueryPageContext is like QueryPage, using ctx for the statements.
+func (b SelectBuilder) QueryPageContext(ctx context.Context, count PageCount, scan func(RowScanner) error) (PageResult, error) {
+	data := builder.GetStruct(b).(selectData)
+	if data.RunWith == nil {
+		return PageResult{}, RunnerNotSet
+	}
+	runner, ok := data.RunWith.(pageRunnerContext)
+	if !ok {
+		if _, ok := data.RunWith.(QueryerContext); !ok {
+			return PageResult{}, RunnerNotQueryRunner
+		}
+		return PageResult{}, NoContextSupport
+	}
+
+	if db, ok := runnerDB(data.RunWith).(txBeginnerContext); ok && count == CountSeparately {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return PageResult{}, err
+		}
+		defer tx.Rollback()
+		runner = &stdsqlRunner{tx}
+
+		result, err := b.queryPage(newPageRunnerContext(ctx, runner), count, scan)
+		if err != nil {
+			return result, err
+		}
+		return result, tx.Commit()
+	}
+	return b.queryPage(newPageRunnerContext(ctx, runner), count, scan)
+}
+
+func newPageRunnerContext(ctx context.Context, runner pageRunnerContext) pageRunner {
+	return pageRunner{
+		query: func(s Sqlizer) (pageRows, error) {
+			rows, err := QueryContextWith(ctx, runner, s)
+			if err != nil {
+				return nil, err
+			}
+			return rows, nil
+		},
+		queryRow: func(s Sqlizer) RowScanner {
+			return QueryRowContextWith(ctx, runner, s)
+		},
+	}
+}
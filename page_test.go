@@ -0,0 +1,178 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryPageErrors(t *testing.T) {
+	scan := func(RowScanner) error { return nil }
+
+	_, err := Select("a").From("t").Limit(10).QueryPage(CountSeparately, scan)
+	assert.Equal(t, RunnerNotSet, err)
+
+	db := &DBStub{}
+	b := Select("a").From("t").RunWith(db)
+
+	_, err = b.QueryPage(CountSeparately, scan)
+	assert.EqualError(t, err, "QueryPage requires the query to have a Limit or LimitRowNum")
+
+	_, err = b.Limit(0).QueryPage(CountSeparately, scan)
+	assert.Error(t, err)
+
+	_, err = b.Distinct().Limit(10).QueryPage(CountOver, scan)
+	assert.Error(t, err)
+
+	_, err = b.LimitRowNum(10).QueryPage(CountOver, scan)
+	assert.Error(t, err)
+
+	assert.Empty(t, db.LastQuerySql)
+}
+
+// pageRowsStub is a page of n rows, whose COUNT(*) OVER () column is total.
+type pageRowsStub struct {
+	n      int
+	total  uint64
+	next   int
+	closed bool
+}
+
+func (r *pageRowsStub) Next() bool {
+	if r.next < r.n {
+		r.next++
+		return true
+	}
+	return false
+}
+
+func (r *pageRowsStub) Scan(dest ...interface{}) error {
+	for _, d := range dest {
+		if total, ok := d.(*uint64); ok {
+			*total = r.total
+		}
+	}
+	return nil
+}
+
+func (r *pageRowsStub) Err() error {
+	return nil
+}
+
+func (r *pageRowsStub) Close() error {
+	r.closed = true
+	return nil
+}
+
+// countStub is the row of a count query counting its value.
+type countStub uint64
+
+func (c countStub) Scan(dest ...interface{}) error {
+	*dest[0].(*uint64) = uint64(c)
+	return nil
+}
+
+// stubPageRunner runs the statements of QueryPage with db, returning rows for
+// the page and total for the count query.
+func stubPageRunner(db *DBStub, rows *pageRowsStub, total uint64) pageRunner {
+	return pageRunner{
+		query: func(s Sqlizer) (pageRows, error) {
+			if _, err := QueryWith(db, s); err != nil {
+				return nil, err
+			}
+			return rows, nil
+		},
+		queryRow: func(s Sqlizer) RowScanner {
+			QueryRowWith(db, s)
+			return countStub(total)
+		},
+	}
+}
+
+func TestQueryPageTotal(t *testing.T) {
+	b := Select("a").From("t").OrderBy("a")
+	tests := []struct {
+		query    SelectBuilder
+		rows     int
+		counted  bool
+		expected PageResult
+	}{
+		// Pages that are not full end the rows, unless they are empty and
+		// past the first page.
+		{b.Limit(10), 4, false, PageResult{Total: 4, Page: 1, PerPage: 10, Rows: 4}},
+		{b.Limit(10).Offset(20), 3, false, PageResult{Total: 23, Page: 3, PerPage: 10, Rows: 3}},
+		{b.Limit(10), 0, false, PageResult{Total: 0, Page: 1, PerPage: 10}},
+		{b.Limit(10).Offset(10), 10, true, PageResult{Total: 35, Page: 2, PerPage: 10, Rows: 10, HasNext: true}},
+		{b.Limit(10).Offset(50), 0, true, PageResult{Total: 35, Page: 6, PerPage: 10}},
+		{b.LimitRowNum(10).Page(4), 5, false, PageResult{Total: 35, Page: 4, PerPage: 10, Rows: 5}},
+	}
+	for _, test := range tests {
+		db := &DBStub{}
+		rows := &pageRowsStub{n: test.rows}
+		scanned := 0
+		result, err := test.query.queryPage(stubPageRunner(db, rows, 35), CountSeparately, func(RowScanner) error {
+			scanned++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result)
+		assert.Equal(t, test.rows, scanned)
+		assert.True(t, rows.closed)
+		if test.counted {
+			assert.Equal(t, "SELECT COUNT(*) FROM t", db.LastQueryRowSql)
+		} else {
+			assert.Empty(t, db.LastQueryRowSql)
+		}
+	}
+}
+
+func TestQueryPageCountOver(t *testing.T) {
+	b := Select("a").From("t").Where("b = ?", 1).OrderBy("a").Limit(10).Offset(10)
+
+	db := &DBStub{}
+	var totals []uint64
+	result, err := b.queryPage(stubPageRunner(db, &pageRowsStub{n: 10, total: 42}, 35), CountOver, func(row RowScanner) error {
+		var a string
+		var total uint64
+		err := row.Scan(&a, &total)
+		totals = append(totals, total)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a, COUNT(*) OVER () FROM t WHERE b = ? ORDER BY a LIMIT 10 OFFSET 10", db.LastQuerySql)
+	assert.Equal(t, []interface{}{1}, db.LastQueryArgs)
+	assert.Empty(t, db.LastQueryRowSql)
+	assert.Equal(t, PageResult{Total: 42, Page: 2, PerPage: 10, Rows: 10, HasNext: true}, result)
+
+	// An empty page past the first has no total column to read it from.
+	db = &DBStub{}
+	result, err = b.Offset(50).queryPage(stubPageRunner(db, &pageRowsStub{}, 35), CountOver, func(RowScanner) error {
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM t WHERE b = ?", db.LastQueryRowSql)
+	assert.Equal(t, PageResult{Total: 35, Page: 6, PerPage: 10}, result)
+}
+
+func TestQueryPageStrictIdents(t *testing.T) {
+	b := StatementBuilder.StrictIdents().Select("a").From("t").Where(Eq{"b": 1}).OrderBy("a").Limit(10).Offset(10)
+
+	db := &DBStub{}
+	result, err := b.queryPage(stubPageRunner(db, &pageRowsStub{n: 10, total: 42}, 35), CountOver, func(row RowScanner) error {
+		var a string
+		var total uint64
+		return row.Scan(&a, &total)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a, COUNT(*) OVER () FROM t WHERE b = ? ORDER BY a LIMIT 10 OFFSET 10", db.LastQuerySql)
+	assert.Equal(t, uint64(42), result.Total)
+
+	db = &DBStub{}
+	result, err = b.queryPage(stubPageRunner(db, &pageRowsStub{n: 10}, 35), CountSeparately, func(row RowScanner) error {
+		var a string
+		return row.Scan(&a)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM t WHERE b = ?", db.LastQueryRowSql)
+	assert.Equal(t, uint64(35), result.Total)
+}
@@ -0,0 +1,179 @@
+package squirrel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// PlaceholderMismatch is wrapped by the errors returned by ToSql in checked
+// mode for statements whose number of placeholders is not their number of
+// arguments. See StatementBuilderType.CheckParams.
+var PlaceholderMismatch = errors.New("placeholders do not match arguments")
+
+// TooManyParams is wrapped by the errors returned by ToSql in checked mode for
+// statements with more bind parameters than their Dialect allows. See
+// StatementBuilderType.CheckParams.
+var TooManyParams = errors.New("too many bind parameters")
+
+// labeledPart is a part of a statement labeled after the builder method that
+// added it, e.g. "Where 2", for the errors of checkParams.
+type labeledPart struct {
+	label string
+	part  Sqlizer
+}
+
+// labelParts labels parts with label followed by their 1-based index.
+func labelParts(label string, parts []Sqlizer) []labeledPart {
+	labeled := make([]labeledPart, len(parts))
+	for i, p := range parts {
+		labeled[i] = labeledPart{label: fmt.Sprintf("%s %d", label, i+1), part: p}
+	}
+	return labeled
+}
+
+// labelSetClauses labels the values of clauses that are expressions with
+// label followed by their column.
+func labelSetClauses(label string, clauses []setClause) []labeledPart {
+	var labeled []labeledPart
+	for _, c := range clauses {
+		if s, ok := c.value.(Sqlizer); ok {
+			labeled = append(labeled, labeledPart{label: label + " " + c.column, part: s})
+		}
+	}
+	return labeled
+}
+
+// argPart is a placeholder bound to value.
+type argPart struct {
+	value interface{}
+}
+
+func (p argPart) ToSql() (string, []interface{}, error) {
+	return "?", []interface{}{p.value}, nil
+}
+
+func (es exprs) sqlizers() []Sqlizer {
+	parts := make([]Sqlizer, len(es))
+	for i, e := range es {
+		parts[i] = e
+	}
+	return parts
+}
+
+// countPlaceholders returns the number of ? placeholders of sql, a statement
+// for d.
+func countPlaceholders(d Dialect, sql string) int {
+	_, n, _ := rewritePlaceholders(sql, "", backslashesOf(d), func(*bytes.Buffer, int) error { return nil })
+	return n
+}
+
+// checkParams returns an error wrapping PlaceholderMismatch if sql, a
+// statement with ? placeholders, does not have a placeholder per argument,
+// naming the first of its parts that does not either, or an error wrapping
+// TooManyParams if it has more arguments than d allows. Arguments bound to
+// @name parameters passed through for d have no placeholder.
+func checkParams(d Dialect, sql string, args []interface{}, parts []labeledPart) error {
+	if n := countPlaceholders(d, sql); n != len(args)-countNamedParams(args) {
+		for _, p := range parts {
+			if p.part == nil {
+				continue
+			}
+			partSql, partArgs, err := toSqlDialect(d, p.part)
+			if err != nil {
+				return err
+			}
+			if m, k := countPlaceholders(d, partSql), len(partArgs)-countNamedParams(partArgs); m != k {
+				return fmt.Errorf("%w: %s has %d placeholders for %d args: %s",
+					PlaceholderMismatch, p.label, m, k, partSql)
+			}
+		}
+		return fmt.Errorf("%w: statement has %d placeholders for %d args", PlaceholderMismatch, n, len(args)-countNamedParams(args))
+	}
+
+	d = dialectOr(d)
+	if max := d.maxParams(); max > 0 && len(args) > max {
+		return fmt.Errorf("%w: statement has %d, over the %s limit of %d", TooManyParams, len(args), d.Name(), max)
+	}
+	return nil
+}
+
+func (d *selectData) checkParams(sql string, args []interface{}) error {
+	var parts []labeledPart
+	parts = append(parts, labelParts("Prefix", d.Prefixes.sqlizers())...)
+	for _, c := range d.CTEs {
+		parts = append(parts, labeledPart{label: "With " + c.name, part: c.query})
+	}
+	parts = append(parts, labelParts("Column", d.Columns)...)
+	parts = append(parts, labeledPart{label: "From", part: d.From})
+	parts = append(parts, labelParts("Join", d.Joins)...)
+	parts = append(parts, labelParts("Where", d.WhereParts)...)
+	if len(d.WhereParts) == 0 {
+		// Only rendered without WhereParts, and then only those with args.
+		parts = append(parts, labelParts("Where", d.WherePartsEscapeEmptyParams)...)
+	}
+	parts = append(parts, labelParts("Having", d.HavingParts)...)
+	parts = append(parts, labelParts("Window", d.Windows)...)
+	parts = append(parts, labelParts("OrderBy", d.OrderByParts)...)
+	parts = append(parts, labelParts("Suffix", d.Suffixes.sqlizers())...)
+	return checkParams(d.Dialect, sql, args, parts)
+}
+
+func (d *compoundData) checkParams(sql string, args []interface{}) error {
+	var parts []labeledPart
+	parts = append(parts, labelParts("Prefix", d.Prefixes.sqlizers())...)
+	for i, p := range d.Parts {
+		parts = append(parts, labeledPart{label: fmt.Sprintf("Select %d", i+1), part: p.query})
+	}
+	parts = append(parts, labelParts("OrderBy", d.OrderByParts)...)
+	parts = append(parts, labelParts("Suffix", d.Suffixes.sqlizers())...)
+	return checkParams(d.Dialect, sql, args, parts)
+}
+
+func (d *insertData) checkParams(sql string, args []interface{}) error {
+	var parts []labeledPart
+	parts = append(parts, labelParts("Prefix", d.Prefixes.sqlizers())...)
+	for i, row := range d.Values {
+		values := concatExpr{"("}
+		for j, val := range row {
+			if j > 0 {
+				values = append(values, ",")
+			}
+			if e, ok := val.(expr); ok {
+				values = append(values, e)
+			} else {
+				values = append(values, argPart{val})
+			}
+		}
+		values = append(values, ")")
+		parts = append(parts, labeledPart{label: fmt.Sprintf("Values row %d", i+1), part: values})
+	}
+	if d.Select != nil {
+		parts = append(parts, labeledPart{label: "Select", part: *d.Select})
+	}
+	parts = append(parts, labelSetClauses("DoUpdateSet", d.ConflictSetClauses)...)
+	parts = append(parts, labelParts("DoUpdateWhere", d.ConflictWhereParts)...)
+	parts = append(parts, labelParts("Suffix", d.Suffixes.sqlizers())...)
+	return checkParams(d.Dialect, sql, args, parts)
+}
+
+func (d *updateData) checkParams(sql string, args []interface{}) error {
+	var parts []labeledPart
+	parts = append(parts, labelParts("Prefix", d.Prefixes.sqlizers())...)
+	parts = append(parts, labelSetClauses("Set", d.SetClauses)...)
+	parts = append(parts, labeledPart{label: "From", part: d.From})
+	parts = append(parts, labelParts("Join", d.Joins)...)
+	parts = append(parts, labelParts("Where", d.WhereParts)...)
+	parts = append(parts, labelParts("Suffix", d.Suffixes.sqlizers())...)
+	return checkParams(d.Dialect, sql, args, parts)
+}
+
+func (d *deleteData) checkParams(sql string, args []interface{}) error {
+	var parts []labeledPart
+	parts = append(parts, labelParts("Prefix", d.Prefixes.sqlizers())...)
+	parts = append(parts, labelParts("Using", d.Using)...)
+	parts = append(parts, labelParts("Join", d.Joins)...)
+	parts = append(parts, labelParts("Where", d.WhereParts)...)
+	parts = append(parts, labelParts("Suffix", d.Suffixes.sqlizers())...)
+	return checkParams(d.Dialect, sql, args, parts)
+}
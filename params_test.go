@@ -0,0 +1,94 @@
+package squirrel
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckParams(t *testing.T) {
+	b := StatementBuilder.CheckParams()
+
+	_, _, err := b.Select("a").From("t").Where("b = ?", 1).Where("c = ? AND d = ?", 2).ToSql()
+	assert.True(t, errors.Is(err, PlaceholderMismatch))
+	assert.EqualError(t, err, "placeholders do not match arguments: Where 2 has 2 placeholders for 1 args: c = ? AND d = ?")
+
+	_, _, err = b.Select("a").From("t").
+		WhereEscapeEmptyParams("b = ?", 1).
+		WhereEscapeEmptyParams("d = ? AND e = ?", 2).
+		ToSql()
+	assert.EqualError(t, err, "placeholders do not match arguments: Where 2 has 2 placeholders for 1 args: d = ? AND e = ?")
+
+	_, _, err = b.Insert("t").Columns("a", "b").Values(1, 2).Values(3, Expr("? + ?", 4)).ToSql()
+	assert.EqualError(t, err, "placeholders do not match arguments: Values row 2 has 3 placeholders for 2 args: (?,? + ?)")
+
+	_, _, err = b.Update("t").Set("a", Expr("a + ?")).Where("id = ?", 1).ToSql()
+	assert.EqualError(t, err, "placeholders do not match arguments: Set a has 1 placeholders for 0 args: a + ?")
+
+	_, _, err = b.Delete("t").Where("id = ?", 1).Suffix("LIMIT ?", 1, 2).ToSql()
+	assert.EqualError(t, err, "placeholders do not match arguments: Suffix 1 has 1 placeholders for 2 args: LIMIT ?")
+
+	_, _, err = b.Union(Select("a").From("t"), Select("a").From("u").Where("b = ?")).ToSql()
+	assert.EqualError(t, err, "placeholders do not match arguments: Select 2 has 1 placeholders for 0 args: SELECT a FROM u WHERE b = ?")
+
+	_, _, err = b.Select("a").Where("b = ?", 1).Prefix("WITH x AS (SELECT ?)").ToSql()
+	assert.EqualError(t, err, "placeholders do not match arguments: Prefix 1 has 1 placeholders for 0 args: WITH x AS (SELECT ?)")
+}
+
+func TestCheckParamsValid(t *testing.T) {
+	b := StatementBuilder.CheckParams().Dialect(PostgreSQL)
+
+	sql, args, err := b.Select("a").
+		From("t").
+		Where("data ? 'k' AND note <> 'why?'").
+		Where(Eq{"b": []int{1, 2}}).
+		Where("c = :c", map[string]interface{}{"c": 3}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE data ? 'k' AND note <> 'why?' AND b IN ($1,$2) AND c = $3", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	_, _, err = b.Insert("t").Columns("a").Values(Expr("? + ?", 1, 2)).ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = b.Select("a").From("t").Where("x = TRIM(LEADING ? FROM y)", "0").ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = b.Dialect(MySQL).Select("a").From("t").Where(`b = 'it\'s?' AND c = ?`, 1).ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = StatementBuilder.Select("a").From("t").Where("b = ? AND c = ?", 1).ToSql()
+	assert.NoError(t, err)
+}
+
+func TestCheckParamsNamedArgs(t *testing.T) {
+	b := StatementBuilder.CheckParams().Dialect(SQLServer)
+
+	sqlStr, args, err := b.Update("t").
+		Set("a", 1).
+		Where("id = @id AND owner = @owner OR parent = @id", sql.Named("id", 7), sql.Named("owner", "me")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = @p1 WHERE id = @id AND owner = @owner OR parent = @id", sqlStr)
+	assert.Len(t, args, 3)
+
+	_, _, err = b.Select("a").From("t").Where("id = @id", sql.Named("id", 7)).Where("b = ? AND c = ?", 1).ToSql()
+	assert.EqualError(t, err, "placeholders do not match arguments: Where 2 has 2 placeholders for 1 args: b = ? AND c = ?")
+}
+
+func TestCheckParamsLimit(t *testing.T) {
+	ids := make([]int, 1000)
+	b := StatementBuilder.CheckParams().Dialect(SQLite)
+
+	_, _, err := b.Select("a").From("t").Where(Eq{"id": ids}).ToSql()
+	assert.True(t, errors.Is(err, TooManyParams))
+	assert.EqualError(t, err, "too many bind parameters: statement has 1000, over the sqlite3 limit of 999")
+
+	_, _, err = b.Select("a").From("t").Where(Eq{"id": ids[:999]}).ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = b.Dialect(PostgreSQL).Select("a").From("t").Where(Eq{"id": ids}).ToSql()
+	assert.NoError(t, err)
+}
@@ -15,23 +15,41 @@ func newPart(pred interface{}, args ...interface{}) Sqlizer {
 }
 
 func (p part) ToSql() (sql string, args []interface{}, err error) {
+	return p.toSqlDialect(nil)
+}
+
+func (p part) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
 	switch pred := p.pred.(type) {
 	case nil:
 		// no-op
 	case Sqlizer:
-		sql, args, err = pred.ToSql()
+		sql, args, err = toSqlDialect(d, pred)
 	case string:
-		sql = pred
-		args = p.args
+		sql, args, err = bindNamedDialect(d, pred, p.args)
 	default:
 		err = fmt.Errorf("expected string or Sqlizer, not %T", pred)
 	}
 	return
 }
 
+// nestedToSql returns the SQL of s without finalizing its placeholders when s
+// supports it, so that it can be embedded in an enclosing statement whose
+// PlaceholderFormat numbers all placeholders at once.
+func nestedToSql(s Sqlizer) (string, []interface{}, error) {
+	if raw, ok := s.(rawSqlizer); ok {
+		return raw.toSqlRaw()
+	}
+	return s.ToSql()
+}
+
 func appendToSql(parts []Sqlizer, w io.Writer, sep string, args []interface{}) ([]interface{}, error) {
+	return appendToSqlDialect(nil, parts, w, sep, args)
+}
+
+// appendToSqlDialect is like appendToSql, rendering the parts for d.
+func appendToSqlDialect(d Dialect, parts []Sqlizer, w io.Writer, sep string, args []interface{}) ([]interface{}, error) {
 	for i, p := range parts {
-		partSql, partArgs, err := p.ToSql()
+		partSql, partArgs, err := toSqlDialect(d, p)
 		if err != nil {
 			return nil, err
 		} else if len(partSql) == 0 {
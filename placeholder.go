@@ -2,7 +2,9 @@ package squirrel
 
 import (
 	"bytes"
+	"database/sql"
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -14,10 +16,114 @@ type PlaceholderFormat interface {
 	ReplacePlaceholders(sql string) (string, error)
 }
 
+// placeholderDebugger is implemented by the PlaceholderFormats numbering
+// placeholders, and by the builders, to give DebugSqlizer the prefix of their
+// placeholders, e.g. "$" or "@p".
 type placeholderDebugger interface {
 	debugPlaceholder() string
 }
 
+// debugPlaceholder returns the prefix of the placeholders of f, or "?".
+func debugPlaceholder(f PlaceholderFormat) string {
+	if d, ok := f.(placeholderDebugger); ok {
+		return d.debugPlaceholder()
+	}
+	return "?"
+}
+
+// argsFormatter is implemented by the PlaceholderFormats that also convert
+// the arguments of the placeholders they replace.
+type argsFormatter interface {
+	formatArgs(args []interface{}) []interface{}
+}
+
+// formatArgs returns args as converted by f.
+func formatArgs(f PlaceholderFormat, args []interface{}) []interface{} {
+	if a, ok := f.(argsFormatter); ok {
+		return a.formatArgs(args)
+	}
+	return args
+}
+
+// namedArgsKeeper is implemented by the PlaceholderFormats of drivers binding
+// sql.NamedArg arguments to @name parameters, such as SQL Server's, for which
+// the builders pass the @name parameters bound by sql.NamedArg arguments
+// through instead of binding them to ? placeholders.
+type namedArgsKeeper interface {
+	keepsNamedArgs() bool
+}
+
+// keepsNamedArgs reports whether f keeps @name parameters bound by
+// sql.NamedArg arguments.
+func keepsNamedArgs(f PlaceholderFormat) bool {
+	k, ok := f.(namedArgsKeeper)
+	return ok && k.keepsNamedArgs()
+}
+
+// finalizePlaceholders replaces the placeholders of sql, a statement of a
+// builder with args, as f does, and returns the arguments to run it with.
+//
+// When f keeps @name parameters, their sql.NamedArg arguments are returned once
+// per name, and the other placeholders are numbered after the position of
+// their argument among all arguments, as drivers name them, e.g. @p2 in
+// "a = @a AND b = @p2". Otherwise they are bound to ? placeholders again.
+func finalizePlaceholders(f PlaceholderFormat, sql string, args []interface{}) (string, []interface{}, error) {
+	if countNamedParams(args) > 0 {
+		if keepsNamedArgs(f) {
+			return finalizeNamedParams(f, sql, args)
+		}
+		var err error
+		if sql, args, err = unbindNamedParams(sql, args); err != nil {
+			return "", nil, err
+		}
+	}
+	sql, err := f.ReplacePlaceholders(sql)
+	return sql, formatArgs(f, args), err
+}
+
+// finalizeNamedParams is finalizePlaceholders for formats keeping @name
+// parameters.
+func finalizeNamedParams(f PlaceholderFormat, query string, args []interface{}) (string, []interface{}, error) {
+	prefix := debugPlaceholder(f)
+	named, isNamedArgs := f.(namedArgsFormat)
+
+	var final []interface{}
+	var ordinals []int
+	values := make(map[string]interface{})
+	for _, arg := range args {
+		p, ok := arg.(namedParam)
+		if !ok {
+			ordinals = append(ordinals, len(final)+1)
+			if isNamedArgs {
+				arg = sql.Named(fmt.Sprintf("%s%d", named.prefix[1:], len(final)+1), namedArgValue(arg))
+			}
+			final = append(final, arg)
+			continue
+		}
+		if value, seen := values[p.name]; seen {
+			if !reflect.DeepEqual(value, p.value) {
+				return "", nil, fmt.Errorf("named parameter %q is bound to different values", p.name)
+			}
+			continue
+		}
+		values[p.name] = p.value
+		final = append(final, sql.Named(p.name, p.value))
+	}
+
+	query, _, err := rewritePlaceholders(query, "", backslashAuto, func(buf *bytes.Buffer, i int) error {
+		ordinal := len(final) + i - len(ordinals) + 1
+		if i < len(ordinals) {
+			ordinal = ordinals[i]
+		}
+		fmt.Fprintf(buf, "%s%d", prefix, ordinal)
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return query, final, nil
+}
+
 var (
 	// Question is a PlaceholderFormat instance that leaves placeholders as
 	// question marks.
@@ -30,6 +136,11 @@ var (
 	// Colon is a PlaceholderFormat instance that replaces placeholders with
 	// colon-prefixed positional placeholders (e.g. :1, :2, :3).
 	Colon = colonFormat{}
+
+	// AtP is a PlaceholderFormat instance that replaces placeholders with
+	// "@p"-prefixed positional placeholders (e.g. @p1, @p2, @p3), as expected
+	// by SQL Server drivers.
+	AtP = atpFormat{}
 )
 
 type questionFormat struct{}
@@ -62,6 +173,81 @@ func (colonFormat) debugPlaceholder() string {
 	return ":"
 }
 
+type atpFormat struct{}
+
+func (atpFormat) ReplacePlaceholders(sql string) (string, error) {
+	return replacePositionalPlaceholders(sql, "@p")
+}
+
+func (atpFormat) debugPlaceholder() string {
+	return "@p"
+}
+
+func (atpFormat) keepsNamedArgs() bool {
+	return true
+}
+
+// NamedArgs returns a PlaceholderFormat replacing placeholders with prefix
+// followed by their number, and making ToSql return their arguments as
+// sql.NamedArg values named after them, for drivers binding arguments by name
+// only. The names are the placeholders without the first character of
+// prefix, which must be followed by a letter, e.g.
+//   NamedArgs("@p")
+// replaces placeholders with @p1, @p2... and binds sql.Named("p1", v1),
+// sql.Named("p2", v2)...
+func NamedArgs(prefix string) PlaceholderFormat {
+	return namedArgsFormat{prefix: prefix}
+}
+
+type namedArgsFormat struct {
+	prefix string
+}
+
+func (f namedArgsFormat) ReplacePlaceholders(sql string) (string, error) {
+	if len(f.prefix) < 2 || !isNameStart(f.prefix, 1) {
+		return "", fmt.Errorf("invalid NamedArgs prefix %q: expected a character followed by a letter", f.prefix)
+	}
+	return replacePositionalPlaceholders(sql, f.prefix)
+}
+
+func (f namedArgsFormat) debugPlaceholder() string {
+	return f.prefix
+}
+
+func (f namedArgsFormat) keepsNamedArgs() bool {
+	return len(f.prefix) > 1 && f.prefix[0] == '@' && isNameStart(f.prefix, 1)
+}
+
+func (f namedArgsFormat) formatArgs(args []interface{}) []interface{} {
+	if len(args) == 0 {
+		return args
+	}
+	named := make([]interface{}, len(args))
+	for i, arg := range args {
+		named[i] = sql.Named(fmt.Sprintf("%s%d", f.prefix[1:], i+1), namedArgValue(arg))
+	}
+	return named
+}
+
+// namedArgIndex returns the index of the sql.NamedArg named name in args, or
+// -1 if there is none.
+func namedArgIndex(args []interface{}, name string) int {
+	for i, arg := range args {
+		if named, ok := arg.(sql.NamedArg); ok && named.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// namedArgValue returns the value of arg if it is a sql.NamedArg, or arg.
+func namedArgValue(arg interface{}) interface{} {
+	if named, ok := arg.(sql.NamedArg); ok {
+		return named.Value
+	}
+	return arg
+}
+
 // Placeholders returns a string with count ? placeholders joined with commas.
 func Placeholders(count int) string {
 	if count < 1 {
@@ -72,29 +258,9 @@ func Placeholders(count int) string {
 }
 
 func replacePositionalPlaceholders(sql, prefix string) (string, error) {
-	buf := &bytes.Buffer{}
-	i := 0
-	for {
-		p := strings.Index(sql, "?")
-		if p == -1 {
-			break
-		}
-
-		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
-			buf.WriteString(sql[:p])
-			buf.WriteString("?")
-			if len(sql[p:]) == 1 {
-				break
-			}
-			sql = sql[p+2:]
-		} else {
-			i++
-			buf.WriteString(sql[:p])
-			fmt.Fprintf(buf, "%s%d", prefix, i)
-			sql = sql[p+1:]
-		}
-	}
-
-	buf.WriteString(sql)
-	return buf.String(), nil
+	sql, _, err := rewritePlaceholders(sql, "", backslashAuto, func(buf *bytes.Buffer, i int) error {
+		fmt.Fprintf(buf, "%s%d", prefix, i+1)
+		return nil
+	})
+	return sql, err
 }
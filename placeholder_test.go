@@ -1,6 +1,7 @@
 package squirrel
 
 import (
+	"database/sql"
 	"strings"
 	"testing"
 
@@ -25,6 +26,65 @@ func TestColon(t *testing.T) {
 	assert.Equal(t, "x = :1 AND y = :2", s)
 }
 
+func TestAtP(t *testing.T) {
+	sql := "x = ? AND y = ?"
+	s, _ := AtP.ReplacePlaceholders(sql)
+	assert.Equal(t, "x = @p1 AND y = @p2", s)
+}
+
+func TestNamedArgs(t *testing.T) {
+	s, err := NamedArgs(":arg").ReplacePlaceholders("x = ? AND y = ?")
+	assert.NoError(t, err)
+	assert.Equal(t, "x = :arg1 AND y = :arg2", s)
+
+	_, err = NamedArgs("@").ReplacePlaceholders("x = ?")
+	assert.EqualError(t, err, `invalid NamedArgs prefix "@": expected a character followed by a letter`)
+	_, err = NamedArgs("$1").ReplacePlaceholders("x = ?")
+	assert.Error(t, err)
+}
+
+func TestNamedArgsBuilder(t *testing.T) {
+	sqlStr, args, err := Select("a").
+		From("t").
+		Where(Eq{"b": 1}).
+		Where("c = @c", sql.Named("c", "x")).
+		PlaceholderFormat(NamedArgs("@p")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = @p1 AND c = @p2", sqlStr)
+	assert.Equal(t, []interface{}{sql.Named("p1", 1), sql.Named("p2", "x")}, args)
+
+	sqlStr, args, err = Select("a").From("t").PlaceholderFormat(NamedArgs("@p")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t", sqlStr)
+	assert.Empty(t, args)
+}
+
+func TestFinalizePlaceholders(t *testing.T) {
+	query := "a = ? AND b = @b AND c IN (?,?) AND d = @b"
+	args := []interface{}{1, namedParam{name: "b", value: "x"}, 2, 3, namedParam{name: "b", value: "x"}}
+
+	sqlStr, final, err := finalizePlaceholders(AtP, query, args)
+	assert.NoError(t, err)
+	assert.Equal(t, "a = @p1 AND b = @b AND c IN (@p3,@p4) AND d = @b", sqlStr)
+	assert.Equal(t, []interface{}{1, sql.Named("b", "x"), 2, 3}, final)
+
+	sqlStr, final, err = finalizePlaceholders(NamedArgs("@arg"), query, args)
+	assert.NoError(t, err)
+	assert.Equal(t, "a = @arg1 AND b = @b AND c IN (@arg3,@arg4) AND d = @b", sqlStr)
+	assert.Equal(t, []interface{}{sql.Named("arg1", 1), sql.Named("b", "x"), sql.Named("arg3", 2), sql.Named("arg4", 3)}, final)
+
+	sqlStr, final, err = finalizePlaceholders(Dollar, query, args)
+	assert.NoError(t, err)
+	assert.Equal(t, "a = $1 AND b = $2 AND c IN ($3,$4) AND d = $5", sqlStr)
+	assert.Equal(t, []interface{}{1, "x", 2, 3, "x"}, final)
+
+	_, _, err = finalizePlaceholders(AtP, "b = @b OR b = @b", []interface{}{
+		namedParam{name: "b", value: "x"}, namedParam{name: "b", value: "y"},
+	})
+	assert.EqualError(t, err, `named parameter "b" is bound to different values`)
+}
+
 func TestPlaceholders(t *testing.T) {
 	assert.Equal(t, Placeholders(2), "?,?")
 }
@@ -32,13 +92,102 @@ func TestPlaceholders(t *testing.T) {
 func TestEscapeDollar(t *testing.T) {
 	sql := "SELECT uuid, \"data\" #> '{tags}' AS tags FROM nodes WHERE  \"data\" -> 'tags' ??| array['?'] AND enabled = ?"
 	s, _ := Dollar.ReplacePlaceholders(sql)
-	assert.Equal(t, "SELECT uuid, \"data\" #> '{tags}' AS tags FROM nodes WHERE  \"data\" -> 'tags' ?| array['$1'] AND enabled = $2", s)
+	assert.Equal(t, "SELECT uuid, \"data\" #> '{tags}' AS tags FROM nodes WHERE  \"data\" -> 'tags' ?| array['?'] AND enabled = $1", s)
 }
 
 func TestEscapeColon(t *testing.T) {
 	sql := "SELECT uuid, \"data\" #> '{tags}' AS tags FROM nodes WHERE  \"data\" -> 'tags' ??| array['?'] AND enabled = ?"
 	s, _ := Colon.ReplacePlaceholders(sql)
-	assert.Equal(t, "SELECT uuid, \"data\" #> '{tags}' AS tags FROM nodes WHERE  \"data\" -> 'tags' ?| array[':1'] AND enabled = :2", s)
+	assert.Equal(t, "SELECT uuid, \"data\" #> '{tags}' AS tags FROM nodes WHERE  \"data\" -> 'tags' ?| array['?'] AND enabled = :1", s)
+}
+
+func TestPlaceholdersSkipLiterals(t *testing.T) {
+	tests := []struct{ sql, expected string }{
+		{"a = ? AND b = 'x?y' AND c = ?", "a = $1 AND b = 'x?y' AND c = $2"},
+		{"a = 'it''s ?' AND b = ?", "a = 'it''s ?' AND b = $1"},
+		{"a = E'\\' ?' AND b = ?", "a = E'\\' ?' AND b = $1"},
+		{`"we?ird" = ?`, `"we?ird" = $1`},
+		{"`we?ird` = ?", "`we?ird` = $1"},
+		{"a = ? -- why?\nAND b = ?", "a = $1 -- why?\nAND b = $2"},
+		{"a = ? /* why? /* really? */ ? */ AND b = ?", "a = $1 /* why? /* really? */ ? */ AND b = $2"},
+		{"a = $$what?$$ AND b = $tag$ $$?$$ $tag$ AND c = ?", "a = $$what?$$ AND b = $tag$ $$?$$ $tag$ AND c = $1"},
+		{"a = 'unterminated ?", "a = 'unterminated ?"},
+	}
+	for _, test := range tests {
+		s, err := Dollar.ReplacePlaceholders(test.sql)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, s)
+	}
+}
+
+func TestPlaceholdersAfterWords(t *testing.T) {
+	tests := []struct{ sql, expected string }{
+		{"RETURNING id INTO ?", "RETURNING id INTO $1"},
+		{"TRIM(LEADING ? FROM x)", "TRIM(LEADING $1 FROM x)"},
+		{"GROUP_CONCAT(x SEPARATOR ?)", "GROUP_CONCAT(x SEPARATOR $1)"},
+		{"ROWS ? PRECEDING", "ROWS $1 PRECEDING"},
+		{"x = ? OR ? IS NULL", "x = $1 OR $2 IS NULL"},
+	}
+	for _, test := range tests {
+		s, err := Dollar.ReplacePlaceholders(test.sql)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, s)
+	}
+}
+
+func TestPlaceholdersBackslashEscapes(t *testing.T) {
+	tests := []struct{ sql, expected string }{
+		// MySQL's escapes are only read when the string would not end.
+		{`x = ? AND y = 'it\'s' AND z = ?`, `x = $1 AND y = 'it\'s' AND z = $2`},
+		{`x = ? AND y = "say \"hi?" AND z = ?`, `x = $1 AND y = "say \"hi?" AND z = $2`},
+		{`x = ? AND y = 'C:\' AND z = ?`, `x = $1 AND y = 'C:\' AND z = $2`},
+		{`x = ? AND y = E'it\'s?' AND z = ?`, `x = $1 AND y = E'it\'s?' AND z = $2`},
+	}
+	for _, test := range tests {
+		s, err := Dollar.ReplacePlaceholders(test.sql)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, s)
+	}
+}
+
+func TestPlaceholdersJsonbOperators(t *testing.T) {
+	tests := []struct{ sql, expected string }{
+		{"data ? 'key'", "data ? 'key'"},
+		{"data ?| array['a']", "data ?| array['a']"},
+		{"data ?& array['a']", "data ?& array['a']"},
+		{"data ? E'k\\'s'", "data ? E'k\\'s'"},
+		{"data ? ?", "data ? $1"},
+		{"data->'tags' ?| array[?, ?]", "data->'tags' ?| array[$1, $2]"},
+		{"(data) ?& ? AND x = ?", "(data) ?& $1 AND x = $2"},
+		{"t.data ? 'key' AND id IN (?,?)", "t.data ? 'key' AND id IN ($1,$2)"},
+		{"data::jsonb ? ?", "data::jsonb ? $1"},
+		{"x = ? OR ? IS NULL", "x = $1 OR $2 IS NULL"},
+		{"SELECT ? FROM t LIMIT ? OFFSET ?", "SELECT $1 FROM t LIMIT $2 OFFSET $3"},
+		{"CASE WHEN x THEN ? ELSE ? END", "CASE WHEN x THEN $1 ELSE $2 END"},
+		{"f(?, ?)", "f($1, $2)"},
+		{"x = y || ? || 'z'", "x = y || $1 || 'z'"},
+		{"data ?? 'key'", "data ? 'key'"},
+		{"data ?? '??k' AND note = 'why??' -- really??", "data ? '?k' AND note = 'why?' -- really?"},
+	}
+	for _, test := range tests {
+		s, err := Dollar.ReplacePlaceholders(test.sql)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, s)
+
+		s, err = Colon.ReplacePlaceholders(test.sql)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(test.expected, "$", ":", -1), s)
+	}
+
+	sqlStr, args, err := Select("id").From("nodes").
+		Where("data ? 'k'").
+		Where("data ?| array['a'] AND data ?& array['a']").
+		Where("id = ?", 1).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM nodes WHERE data ? 'k' AND data ?| array['a'] AND data ?& array['a'] AND id = $1", sqlStr)
+	assert.Equal(t, []interface{}{1}, args)
 }
 
 func BenchmarkPlaceholdersArray(b *testing.B) {
@@ -0,0 +1,37 @@
+package squirrel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pseudo tables of the OUTPUT clause holding the rows as written by an
+// INSERT or UPDATE statement and as removed by a DELETE statement.
+const (
+	outputInserted = "INSERTED"
+	outputDeleted  = "DELETED"
+)
+
+// returningClauses returns the clause of a statement returning columns, as
+// rendered for d: either a trailing RETURNING clause, with a leading space, or
+// an OUTPUT clause of the pseudo table for the statement to place.
+func returningClauses(d Dialect, columns []string, pseudoTable string) (returning, output string, err error) {
+	if len(columns) == 0 {
+		return
+	}
+
+	d = dialectOr(d)
+	switch {
+	case d.SupportsReturning():
+		returning = " RETURNING " + strings.Join(columns, ", ")
+	case d.supportsOutput():
+		outputColumns := make([]string, len(columns))
+		for i, column := range columns {
+			outputColumns[i] = pseudoTable + "." + column
+		}
+		output = "OUTPUT " + strings.Join(outputColumns, ", ")
+	default:
+		err = fmt.Errorf("%s dialect does not support RETURNING", d.Name())
+	}
+	return
+}
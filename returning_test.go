@@ -0,0 +1,76 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertReturning(t *testing.T) {
+	b := Insert("users").Columns("name").Values("moe").Returning("id", "created_at")
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name) VALUES (?) RETURNING id, created_at", sql)
+	assert.Equal(t, []interface{}{"moe"}, args)
+
+	sql, _, err = b.OnConflict("name").DoNothing().Suffix("/* x */").Dialect(PostgreSQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name) VALUES ($1) ON CONFLICT (name) DO NOTHING RETURNING id, created_at /* x */", sql)
+
+	sql, _, err = b.Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name) OUTPUT INSERTED.id, INSERTED.created_at VALUES (@p1)", sql)
+
+	sql, _, err = Insert("users").Columns("name").Select(Select("name").From("old")).Returning("*").Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name) OUTPUT INSERTED.* SELECT name FROM old", sql)
+
+	_, _, err = b.Dialect(MySQL).ToSql()
+	assert.EqualError(t, err, "mysql dialect does not support RETURNING")
+}
+
+func TestUpdateReturning(t *testing.T) {
+	b := Update("users").Set("name", "moe").Where(Eq{"id": 1}).Returning("updated_at")
+
+	sql, args, err := b.Dialect(PostgreSQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = $1 WHERE id = $2 RETURNING updated_at", sql)
+	assert.Equal(t, []interface{}{"moe", 1}, args)
+
+	sql, _, err = b.OrderBy("id").Limit(1).Dialect(SQLite).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ? WHERE id = ? RETURNING updated_at ORDER BY id LIMIT 1", sql)
+
+	sql, _, err = b.Limit(1).Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE TOP (1) users SET name = @p1 OUTPUT INSERTED.updated_at WHERE id = @p2", sql)
+
+	_, _, err = b.Dialect(Oracle).ToSql()
+	assert.Error(t, err)
+}
+
+func TestDeleteReturning(t *testing.T) {
+	b := Delete("users").Where(Eq{"id": 1}).Returning("id", "name")
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = ? RETURNING id, name", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _, err = b.Dialect(SQLServer).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users OUTPUT DELETED.id, DELETED.name WHERE id = @p1", sql)
+
+	_, _, err = b.Dialect(MySQL).ToSql()
+	assert.Error(t, err)
+}
+
+func TestReturningScanRunnerNotSet(t *testing.T) {
+	var id int64
+	assert.Equal(t, RunnerNotSet, Insert("t").Values(1).Returning("id").ScanStruct(&id))
+
+	var ids []int64
+	assert.Equal(t, RunnerNotSet, Update("t").Set("a", 1).Returning("id").ScanAll(&ids))
+	assert.Equal(t, RunnerNotSet, Delete("t").Returning("id").ScanAll(&ids))
+}
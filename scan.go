@@ -0,0 +1,219 @@
+package squirrel
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// scanKind is the kind of value a row is scanned into.
+type scanKind int
+
+const (
+	// each column into the struct field mapped to it by its db tag
+	scanStruct scanKind = iota
+	// each column into a map[string]interface{} entry
+	scanMap
+	// the single column into the value
+	scanValue
+)
+
+// scanPlan maps the columns of a query to the parts of the values its rows
+// are scanned into.
+type scanPlan struct {
+	kind    scanKind
+	columns []string
+	// fields holds the index of the struct field of each column, or nil for
+	// the columns discarded by a non-strict scan.
+	fields [][]int
+}
+
+var mapType = reflect.TypeOf(map[string]interface{}{})
+
+func newScanPlan(t reflect.Type, columns []string, strict bool) (*scanPlan, error) {
+	p := &scanPlan{columns: columns}
+
+	switch {
+	case t == mapType:
+		p.kind = scanMap
+		return p, nil
+	case t.Kind() == reflect.Map:
+		return nil, fmt.Errorf("cannot scan into %s; maps must be map[string]interface{}", t)
+	case t.Kind() != reflect.Struct || isScalarStruct(t):
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("cannot scan %d columns into %s", len(columns), t)
+		}
+		p.kind = scanValue
+		return p, nil
+	}
+
+	fields, err := structFields(t)
+	if err != nil {
+		return nil, err
+	}
+	p.kind = scanStruct
+	p.fields = make([][]int, len(columns))
+	for i, column := range columns {
+		for _, f := range fields {
+			if f.column == column {
+				p.fields[i] = f.index
+				break
+			}
+			if p.fields[i] == nil && strings.EqualFold(f.column, column) {
+				p.fields[i] = f.index
+			}
+		}
+		if p.fields[i] == nil && strict {
+			return nil, fmt.Errorf("column %s is not mapped to a field of %s", column, t)
+		}
+	}
+	return p, nil
+}
+
+// targets returns the destinations to scan a row into v, an addressable
+// value of the type of the plan.
+func (p *scanPlan) targets(v reflect.Value) ([]interface{}, error) {
+	targets := make([]interface{}, len(p.columns))
+	switch p.kind {
+	case scanValue:
+		targets[0] = v.Addr().Interface()
+	case scanMap:
+		for i := range targets {
+			targets[i] = new(interface{})
+		}
+	case scanStruct:
+		for i, index := range p.fields {
+			if index == nil {
+				targets[i] = new(interface{})
+				continue
+			}
+			field, err := fieldForScan(v, index)
+			if err != nil {
+				return nil, err
+			}
+			targets[i] = field.Addr().Interface()
+		}
+	}
+	return targets, nil
+}
+
+// finish stores the scanned targets in v, for the kinds of values that are
+// not scanned into directly.
+func (p *scanPlan) finish(v reflect.Value, targets []interface{}) {
+	if p.kind != scanMap {
+		return
+	}
+	m := make(map[string]interface{}, len(p.columns))
+	for i, column := range p.columns {
+		m[column] = *targets[i].(*interface{})
+	}
+	v.Set(reflect.ValueOf(m))
+}
+
+// scan scans the current row of rows into v.
+func (p *scanPlan) scan(rows *sql.Rows, v reflect.Value) error {
+	targets, err := p.targets(v)
+	if err != nil {
+		return err
+	}
+	if err = rows.Scan(targets...); err != nil {
+		return err
+	}
+	p.finish(v, targets)
+	return nil
+}
+
+// fieldForScan returns the field of the struct v at index, allocating the
+// embedded struct pointers on its way.
+func fieldForScan(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot allocate unexported embedded %s", v.Type())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// scanOne scans the first row of rows into dest, a pointer to a struct, a
+// map[string]interface{} or, for single column queries, any value. It
+// returns sql.ErrNoRows if there are no rows.
+func scanOne(rows *sql.Rows, dest interface{}, strict bool) error {
+	defer rows.Close()
+
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("expected a non-nil pointer to scan into, not %T", dest)
+	}
+	v = v.Elem()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	plan, err := newScanPlan(v.Type(), columns, strict)
+	if err != nil {
+		return err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := plan.scan(rows, v); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// scanAll scans the rows of rows into dest, a pointer to a slice of values as
+// accepted by scanOne or pointers to them.
+func scanAll(rows *sql.Rows, dest interface{}, strict bool) error {
+	defer rows.Close()
+
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected a pointer to a slice to scan into, not %T", dest)
+	}
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	plan, err := newScanPlan(elemType, columns, strict)
+	if err != nil {
+		return err
+	}
+
+	slice.Set(slice.Slice(0, 0))
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		if err := plan.scan(rows, elem.Elem()); err != nil {
+			return err
+		}
+		if isPtr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return rows.Close()
+}
@@ -0,0 +1,63 @@
+package squirrel
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type scanTestRow struct {
+	structTestBase
+	Name  string         `db:"name"`
+	Email sql.NullString `db:"email"`
+	Age   *int           `db:"age"`
+}
+
+func TestScanPlanStruct(t *testing.T) {
+	plan, err := newScanPlan(reflect.TypeOf(scanTestRow{}), []string{"ID", "name", "extra"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, scanStruct, plan.kind)
+	assert.Equal(t, [][]int{{0, 0}, {1}, nil}, plan.fields)
+
+	_, err = newScanPlan(reflect.TypeOf(scanTestRow{}), []string{"name", "extra"}, true)
+	assert.EqualError(t, err, "column extra is not mapped to a field of squirrel.scanTestRow")
+}
+
+func TestScanPlanTargets(t *testing.T) {
+	plan, err := newScanPlan(reflect.TypeOf(scanTestRow{}), []string{"id", "age", "extra"}, false)
+	assert.NoError(t, err)
+
+	var row scanTestRow
+	targets, err := plan.targets(reflect.ValueOf(&row).Elem())
+	assert.NoError(t, err)
+	assert.Equal(t, &row.ID, targets[0])
+	assert.Equal(t, &row.Age, targets[1])
+	assert.IsType(t, new(interface{}), targets[2])
+}
+
+func TestScanPlanValues(t *testing.T) {
+	plan, err := newScanPlan(reflect.TypeOf(time.Time{}), []string{"created_at"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, scanValue, plan.kind)
+
+	_, err = newScanPlan(reflect.TypeOf(0), []string{"a", "b"}, false)
+	assert.Error(t, err)
+
+	plan, err = newScanPlan(reflect.TypeOf(map[string]interface{}{}), []string{"a", "b"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, scanMap, plan.kind)
+
+	_, err = newScanPlan(reflect.TypeOf(map[string]string{}), []string{"a"}, false)
+	assert.Error(t, err)
+}
+
+func TestScanStructRunnerNotSet(t *testing.T) {
+	var row scanTestRow
+	assert.Equal(t, RunnerNotSet, Select("name").From("users").ScanStruct(&row))
+
+	var rows []scanTestRow
+	assert.Equal(t, RunnerNotSet, Select("name").From("users").ScanAll(&rows))
+}
@@ -12,8 +12,10 @@ import (
 
 type selectData struct {
 	PlaceholderFormat           PlaceholderFormat
+	Dialect                     Dialect
 	RunWith                     BaseRunner
 	Prefixes                    exprs
+	CTEs                        ctes
 	Options                     []string
 	Columns                     []Sqlizer
 	From                        Sqlizer
@@ -22,38 +24,31 @@ type selectData struct {
 	WherePartsEscapeEmptyParams []Sqlizer
 	GroupBys                    []string
 	HavingParts                 []Sqlizer
+	Windows                     []Sqlizer
 	OrderByParts                []Sqlizer
 	Limit                       string
 	LimitRowNum                 string // for oracle only
 	Page                        string // for oracle only
 	CountAll                    bool   // for oracle only
 	Offset                      string
+	LockMode                    string
+	LockOf                      []string
+	LockWait                    string
 	Suffixes                    exprs
+	StrictScan                  bool
+	statementOptions
 }
 
 func (d *selectData) Exec() (sql.Result, error) {
-	if d.RunWith == nil {
-		return nil, RunnerNotSet
-	}
-	return ExecWith(d.RunWith, d)
+	return execWithRunner(d.RunWith, d)
 }
 
 func (d *selectData) Query() (*sql.Rows, error) {
-	if d.RunWith == nil {
-		return nil, RunnerNotSet
-	}
-	return QueryWith(d.RunWith, d)
+	return queryWithRunner(d.RunWith, d)
 }
 
 func (d *selectData) QueryRow() RowScanner {
-	if d.RunWith == nil {
-		return &Row{err: RunnerNotSet}
-	}
-	queryRower, ok := d.RunWith.(QueryRower)
-	if !ok {
-		return &Row{err: RunnerNotQueryRunner}
-	}
-	return QueryRowWith(queryRower, d)
+	return queryRowWithRunner(d.RunWith, d)
 }
 
 func (d *selectData) ToSql() (sqlStr string, args []interface{}, err error) {
@@ -61,8 +56,13 @@ func (d *selectData) ToSql() (sqlStr string, args []interface{}, err error) {
 	if err != nil {
 		return
 	}
+	if d.CheckParams {
+		if err = d.checkParams(sqlStr, args); err != nil {
+			return
+		}
+	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sqlStr)
+	sqlStr, args, err = finalizePlaceholders(d.PlaceholderFormat, sqlStr, args)
 	return
 }
 
@@ -95,14 +95,64 @@ func (d *selectData) toSql() (sqlStr string, args []interface{}, err error) {
 		err = fmt.Errorf("select statements must have at least one result column")
 		return
 	}
+	if d.StrictIdents {
+		if err = d.checkIdents(); err != nil {
+			return
+		}
+	}
 
 	sql := &bytes.Buffer{}
 
 	if len(d.Prefixes) > 0 {
-		args, _ = d.Prefixes.AppendToSql(sql, " ", args)
+		args, err = d.Prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
+	if len(d.CTEs) > 0 {
+		args, err = d.CTEs.AppendToSql(sql, args)
+		if err != nil {
+			return
+		}
+	}
+
+	// The prefixes and WITH clause stay in front of the queries wrapping a
+	// query paginated with ROW_NUMBER.
+	headLen, headArgs := sql.Len(), len(args)
+
+	limit, offset, err := d.limitOffset()
+	if err != nil {
+		return
+	}
+
+	pagination := d.pagination()
+	top := pagination == paginationTop && len(limit) > 0 && len(offset) == 0
+	rowNumber := pagination == paginationRowNumber && (len(limit) > 0 || len(offset) > 0)
+
+	// ROW_NUMBER() is computed before DISTINCT is applied, so a distinct
+	// query has to be numbered from the outside instead.
+	wrapRowNumber, ownRowNumber := false, false
+	if rowNumber {
+		for _, option := range d.Options {
+			if strings.EqualFold(option, "DISTINCT") {
+				wrapRowNumber = true
+			}
+		}
+	}
+
+	var orderBySql string
+	var orderByArgs []interface{}
+	if len(d.OrderByParts) > 0 {
+		orderBy := &bytes.Buffer{}
+		orderByArgs, err = appendToSqlDialect(d.Dialect, d.OrderByParts, orderBy, ", ", nil)
+		if err != nil {
+			return
+		}
+		orderBySql = orderBy.String()
+	}
+
 	sql.WriteString("SELECT ")
 
 	if len(d.Options) > 0 {
@@ -110,16 +160,33 @@ func (d *selectData) toSql() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(" ")
 	}
 
+	if top {
+		sql.WriteString("TOP (")
+		sql.WriteString(limit)
+		sql.WriteString(") ")
+	}
+
 	if len(d.Columns) > 0 {
-		args, err = appendToSql(d.Columns, sql, ", ", args)
+		columnsStart := sql.Len()
+		args, err = appendToSqlDialect(d.Dialect, d.Columns, sql, ", ", args)
 		if err != nil {
 			return
 		}
+		// Queries written for the original LimitRowNum project their own rnum
+		// column, e.g. "rownum as rnum", which is kept as their row number.
+		ownRowNumber = rowNumber && projectsRowNumber(sql.String()[columnsStart:])
+		wrapRowNumber = wrapRowNumber && !ownRowNumber
+	}
+
+	if rowNumber && !wrapRowNumber && !ownRowNumber {
+		sql.WriteString(", ")
+		sql.WriteString(rowNumberColumn(orderBySql))
+		args = append(args, orderByArgs...)
 	}
 
 	if d.From != nil {
 		sql.WriteString(" FROM ")
-		args, err = appendToSql([]Sqlizer{d.From}, sql, "", args)
+		args, err = appendToSqlDialect(d.Dialect, []Sqlizer{d.From}, sql, "", args)
 		if err != nil {
 			return
 		}
@@ -127,7 +194,7 @@ func (d *selectData) toSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(d.Joins) > 0 {
 		sql.WriteString(" ")
-		args, err = appendToSql(d.Joins, sql, " ", args)
+		args, err = appendToSqlDialect(d.Dialect, d.Joins, sql, " ", args)
 		if err != nil {
 			return
 		}
@@ -135,7 +202,7 @@ func (d *selectData) toSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(d.WhereParts) > 0 {
 		sql.WriteString(" WHERE ")
-		args, err = appendToSql(d.WhereParts, sql, " AND ", args)
+		args, err = appendToSqlDialect(d.Dialect, d.WhereParts, sql, " AND ", args)
 		if err != nil {
 			return
 		}
@@ -154,33 +221,56 @@ func (d *selectData) toSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(d.HavingParts) > 0 {
 		sql.WriteString(" HAVING ")
-		args, err = appendToSql(d.HavingParts, sql, " AND ", args)
+		args, err = appendToSqlDialect(d.Dialect, d.HavingParts, sql, " AND ", args)
 		if err != nil {
 			return
 		}
 	}
 
-	if len(d.OrderByParts) > 0 {
-		sql.WriteString(" ORDER BY ")
-		args, err = appendToSql(d.OrderByParts, sql, ", ", args)
+	if len(d.Windows) > 0 {
+		sql.WriteString(" WINDOW ")
+		args, err = appendToSqlDialect(d.Dialect, d.Windows, sql, ", ", args)
 		if err != nil {
 			return
 		}
 	}
 
-	if len(d.Limit) > 0 {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(d.Limit)
+	if !rowNumber || ownRowNumber {
+		if len(orderBySql) > 0 {
+			sql.WriteString(" ORDER BY ")
+			sql.WriteString(orderBySql)
+			args = append(args, orderByArgs...)
+		} else if pagination == paginationTop && len(offset) > 0 {
+			// SQL Server only accepts OFFSET after an ORDER BY clause.
+			sql.WriteString(" ORDER BY (SELECT NULL)")
+		}
+	}
+	if !rowNumber {
+		if !top {
+			if err = appendPaginationToSql(sql, pagination, limit, offset); err != nil {
+				return
+			}
+		}
+	} else if len(d.LockMode) > 0 {
+		err = fmt.Errorf("rows of a query paginated with ROW_NUMBER cannot be locked")
+		return
 	}
 
-	if len(d.Offset) > 0 {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(d.Offset)
+	if err = appendLockToSql(sql, d.Dialect, d.LockMode, d.LockOf, d.LockWait); err != nil {
+		return
+	}
+	// Oracle rejects FOR UPDATE after OFFSET and FETCH (ORA-02014).
+	if pagination == paginationOffsetFetch && (len(limit) > 0 || len(offset) > 0) && len(d.LockMode) > 0 {
+		err = fmt.Errorf("rows of a query paginated with OFFSET or FETCH cannot be locked")
+		return
 	}
 
 	if len(d.Suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = d.Suffixes.AppendToSql(sql, " ", args)
+		args, err = d.Suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
 	sqlStr = sql.String()
@@ -192,20 +282,24 @@ func (d *selectData) toSql() (sqlStr string, args []interface{}, err error) {
 		sqlStr = sqlStr[:len(sqlStr)-len(" WHERE )")] + ")"
 	}
 
-	if len(d.LimitRowNum) > 0 || len(d.Page) > 0 {
-		oracleSql := &bytes.Buffer{}
-		limit, errLimit := strconv.Atoi(d.LimitRowNum)
-		page, errPage := strconv.Atoi(d.Page)
-		if errLimit != nil || limit <= 0 {
-			limit = 20
+	if rowNumber {
+		head, body := sqlStr[:headLen], sqlStr[headLen:]
+		bodyArgs := args[headArgs:]
+		if wrapRowNumber {
+			body = fmt.Sprintf("SELECT q.*, %s FROM (%s) q", rowNumberColumn(orderBySql), body)
+			// The ORDER BY of the row number comes before the wrapped query.
+			bodyArgs = append(orderByArgs, bodyArgs...)
 		}
-		if errPage != nil || page <= 0 {
-			page = 1
+		if ownRowNumber {
+			body, err = filterOwnRowNumber(body, limit, offset)
+		} else {
+			body, err = filterRowNumber(body, limit, offset)
 		}
-		start := strconv.Itoa(limit*(page-1) + 1)
-		end := strconv.Itoa(limit*page + 1)
-		oracleSql.WriteString("SELECT * FROM (" + sqlStr + ") WHERE rnum >= " + start + " AND " + "rnum < " + end)
-		sqlStr = oracleSql.String()
+		if err != nil {
+			return
+		}
+		sqlStr = head + body
+		args = append(args[:headArgs:headArgs], bodyArgs...)
 	} else if d.CountAll {
 		oracleSql := &bytes.Buffer{}
 		oracleSql.WriteString("SELECT COUNT(*) FROM (" + sqlStr + ")")
@@ -215,10 +309,78 @@ func (d *selectData) toSql() (sqlStr string, args []interface{}, err error) {
 	return
 }
 
+// pagination returns the way the query is paginated.
+func (d *selectData) pagination() paginationStyle {
+	if d.Dialect == nil && (len(d.LimitRowNum) > 0 || len(d.Page) > 0) {
+		// LimitRowNum and Page are meant for Oracle; without a Dialect
+		// telling its version, use the pagination every version supports.
+		return paginationRowNumber
+	}
+	return dialectOr(d.Dialect).pagination()
+}
+
+// limitOffset returns the LIMIT and OFFSET of the query, computing them from
+// LimitRowNum and Page when those are set.
+func (d *selectData) limitOffset() (limit, offset string, err error) {
+	if len(d.LimitRowNum) == 0 && len(d.Page) == 0 {
+		return d.Limit, d.Offset, nil
+	}
+	if len(d.Limit) > 0 || len(d.Offset) > 0 {
+		err = fmt.Errorf("LimitRowNum and Page cannot be combined with Limit and Offset")
+		return
+	}
+	if len(d.LimitRowNum) == 0 {
+		err = fmt.Errorf("Page requires LimitRowNum to be set")
+		return
+	}
+
+	perPage, err := strconv.ParseUint(d.LimitRowNum, 10, 64)
+	if err != nil || perPage == 0 {
+		err = fmt.Errorf("LimitRowNum must be greater than zero, not %s", d.LimitRowNum)
+		return
+	}
+	page := uint64(1)
+	if len(d.Page) > 0 {
+		page, err = strconv.ParseUint(d.Page, 10, 64)
+		if err != nil || page == 0 {
+			err = fmt.Errorf("Page must be greater than zero, not %s", d.Page)
+			return
+		}
+	}
+
+	limit = d.LimitRowNum
+	if page > 1 {
+		offset = strconv.FormatUint(perPage*(page-1), 10)
+	}
+	return
+}
+
 func RemoveIndex(s []Sqlizer, index int) []Sqlizer {
 	return append(s[:index], s[index+1:]...)
 }
 
+// checkIdents checks the identifiers of the query for strict mode.
+func (d *selectData) checkIdents() error {
+	c := newIdentChecker(d.Dialect)
+	if err := c.checkParts(d.Columns, identColumn); err != nil {
+		return err
+	}
+	if d.From != nil {
+		if err := c.checkParts([]Sqlizer{d.From}, identTable); err != nil {
+			return err
+		}
+	}
+	for _, parts := range [][]Sqlizer{d.Joins, d.WhereParts, d.WherePartsEscapeEmptyParams, d.HavingParts} {
+		if err := c.checkExprs(parts); err != nil {
+			return err
+		}
+	}
+	if err := c.checkAll(d.GroupBys, identName); err != nil {
+		return err
+	}
+	return c.checkParts(d.OrderByParts, identOrder)
+}
+
 // Builder
 
 // SelectBuilder builds SQL SELECT statements.
@@ -236,6 +398,16 @@ func (b SelectBuilder) PlaceholderFormat(f PlaceholderFormat) SelectBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(SelectBuilder)
 }
 
+func (b SelectBuilder) debugPlaceholder() string {
+	data := builder.GetStruct(b).(selectData)
+	return debugPlaceholder(data.PlaceholderFormat)
+}
+
+// Dialect sets the Dialect of the query, along with its PlaceholderFormat.
+func (b SelectBuilder) Dialect(d Dialect) SelectBuilder {
+	return setDialect(b, d).(SelectBuilder)
+}
+
 // Runner methods
 
 // RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
@@ -266,6 +438,43 @@ func (b SelectBuilder) Scan(dest ...interface{}) error {
 	return b.QueryRow().Scan(dest...)
 }
 
+// ScanStruct builds and Querys the query with the Runner set by RunWith and
+// scans the first row into dest, mapping columns by name. dest is a pointer to
+// a struct whose fields are mapped to columns by their db tags (see
+// structTag), to a map[string]interface{} or, for a single column, to any
+// value database/sql can scan into. It returns sql.ErrNoRows if there are no
+// rows.
+//
+// Struct fields can be pointers or e.g. sql.NullString to scan NULL values.
+// Columns that are not mapped to a field are discarded, unless StrictScan is
+// set.
+func (b SelectBuilder) ScanStruct(dest interface{}) error {
+	data := builder.GetStruct(b).(selectData)
+	rows, err := data.Query()
+	if err != nil {
+		return err
+	}
+	return scanOne(rows, dest, data.StrictScan)
+}
+
+// ScanAll builds and Querys the query with the Runner set by RunWith and
+// scans all the rows into dest, a pointer to a slice of values or pointers to
+// values as accepted by ScanStruct.
+func (b SelectBuilder) ScanAll(dest interface{}) error {
+	data := builder.GetStruct(b).(selectData)
+	rows, err := data.Query()
+	if err != nil {
+		return err
+	}
+	return scanAll(rows, dest, data.StrictScan)
+}
+
+// StrictScan makes ScanStruct and ScanAll return an error for columns that are
+// not mapped to a field of the struct they scan into.
+func (b SelectBuilder) StrictScan() SelectBuilder {
+	return builder.Set(b, "StrictScan", true).(SelectBuilder)
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -297,6 +506,26 @@ func (b SelectBuilder) Prefix(sql string, args ...interface{}) SelectBuilder {
 	return builder.Append(b, "Prefixes", Expr(sql, args...)).(SelectBuilder)
 }
 
+// With adds a common table expression to the WITH clause of the query.
+//
+// name may include a column list, e.g. "totals(id, total)". The query is
+// rendered without finalizing its placeholders, so a SelectBuilder may be
+// passed regardless of its PlaceholderFormat:
+//   With("recent", Select("id").From("orders").Where("created_at > ?", t))
+func (b SelectBuilder) With(name string, query Sqlizer) SelectBuilder {
+	return builder.Append(b, "CTEs", cte{name: name, query: query}).(SelectBuilder)
+}
+
+// WithRecursive adds a recursive common table expression to the WITH clause of
+// the query. The clause is rendered as WITH RECURSIVE as soon as one of its
+// expressions is recursive.
+//
+// Recursive queries usually combine an anchor and a recursive member with
+// UnionAll.
+func (b SelectBuilder) WithRecursive(name string, query Sqlizer) SelectBuilder {
+	return builder.Append(b, "CTEs", cte{name: name, query: query, recursive: true}).(SelectBuilder)
+}
+
 // Distinct adds a DISTINCT clause to the query.
 func (b SelectBuilder) Distinct() SelectBuilder {
 	return b.Options("DISTINCT")
@@ -402,6 +631,14 @@ func (b SelectBuilder) Having(pred interface{}, rest ...interface{}) SelectBuild
 	return builder.Append(b, "HavingParts", newWherePart(pred, rest...)).(SelectBuilder)
 }
 
+// Window adds a named window to the WINDOW clause of the query, for window
+// functions to refer to with WindowBuilder.Window:
+//   Select("id").Column(RowNumber().Window("w")).From("t").
+//     Window("w", WindowSpec().PartitionBy("a").OrderBy("b"))
+func (b SelectBuilder) Window(name string, spec WindowBuilder) SelectBuilder {
+	return builder.Append(b, "Windows", namedWindow{name: name, spec: spec}).(SelectBuilder)
+}
+
 // OrderByClause adds ORDER BY clause to the query.
 func (b SelectBuilder) OrderByClause(pred interface{}, args ...interface{}) SelectBuilder {
 	return builder.Append(b, "OrderByParts", newPart(pred, args...)).(SelectBuilder)
@@ -416,6 +653,40 @@ func (b SelectBuilder) OrderBy(orderBys ...string) SelectBuilder {
 	return b
 }
 
+// OrderBySort adds ORDER BY clauses sorting by columns, e.g. as parsed from a
+// sort specification by SortFields.Parse.
+func (b SelectBuilder) OrderBySort(columns ...SortColumn) SelectBuilder {
+	for _, c := range columns {
+		b = b.OrderByClause(c)
+	}
+	return b
+}
+
+// Seek adds keyset pagination to the query: an ORDER BY clause for columns and,
+// unless cursor is empty, a WHERE clause selecting the rows sorted after the
+// cursor, the values of columns in the last row of the previous page:
+//   Select("id", "name").From("users").
+//     Seek([]SortColumn{Asc("name"), Asc("id")}, "moe", 42).
+//     Limit(20)
+// renders
+//   SELECT id, name FROM users WHERE (name > ? OR (name = ? AND id > ?))
+//   ORDER BY name ASC, id ASC LIMIT 20
+// A row value comparison, (name, id) > (?, ?), is used instead when all
+// columns have the same direction and default NULL ordering and the Dialect
+// supports it.
+//
+// Cursor values may only be NULL for columns sorted NullsFirst or NullsLast,
+// whose NULL values the WHERE clause then selects as they are sorted.
+//
+// The last sort column should be unique, e.g. the primary key, so that no rows
+// are skipped between pages. See EncodeCursor for passing cursors to clients.
+func (b SelectBuilder) Seek(columns []SortColumn, cursor ...interface{}) SelectBuilder {
+	if len(cursor) > 0 {
+		b = b.Where(keysetPredicate{columns: columns, cursor: cursor})
+	}
+	return b.OrderBySort(columns...)
+}
+
 // Limit sets a LIMIT clause on the query.
 func (b SelectBuilder) Limit(limit uint64) SelectBuilder {
 	return builder.Set(b, "Limit", fmt.Sprintf("%d", limit)).(SelectBuilder)
@@ -436,13 +707,97 @@ func (b SelectBuilder) RemoveOffset() SelectBuilder {
 	return builder.Delete(b, "Offset").(SelectBuilder)
 }
 
+// ForUpdate adds a FOR UPDATE clause, locking the selected rows against
+// concurrent updates, after the query's LIMIT and OFFSET.
+//
+// Locking clauses are rendered for the query's Dialect: SQLite and SQL Server
+// return an error, as do ForShare on Oracle and a locked query paginated on
+// Oracle, which has no LIMIT and cannot lock the rows OFFSET and FETCH keep.
+func (b SelectBuilder) ForUpdate() SelectBuilder {
+	return builder.Set(b, "LockMode", lockUpdate).(SelectBuilder)
+}
+
+// ForShare adds a FOR SHARE clause, locking the selected rows against
+// concurrent updates while allowing other shared locks. MySQL renders it as
+// LOCK IN SHARE MODE unless LockOf, NoWait or SkipLocked is set.
+func (b SelectBuilder) ForShare() SelectBuilder {
+	return builder.Set(b, "LockMode", lockShare).(SelectBuilder)
+}
+
+// LockOf restricts the locking clause to the rows of the given tables (or
+// columns, on Oracle), e.g. FOR UPDATE OF jobs.
+func (b SelectBuilder) LockOf(tables ...string) SelectBuilder {
+	return builder.Extend(b, "LockOf", tables).(SelectBuilder)
+}
+
+// NoWait makes the locking clause fail instead of waiting for rows locked by
+// other transactions.
+func (b SelectBuilder) NoWait() SelectBuilder {
+	return builder.Set(b, "LockWait", lockNoWait).(SelectBuilder)
+}
+
+// SkipLocked makes the locking clause skip rows locked by other transactions,
+// e.g. to poll a job queue from several workers.
+func (b SelectBuilder) SkipLocked() SelectBuilder {
+	return builder.Set(b, "LockWait", lockSkipLocked).(SelectBuilder)
+}
+
+// RemoveLock removes the locking clause.
+func (b SelectBuilder) RemoveLock() SelectBuilder {
+	b = builder.Delete(b, "LockMode").(SelectBuilder)
+	b = builder.Delete(b, "LockOf").(SelectBuilder)
+	return builder.Delete(b, "LockWait").(SelectBuilder)
+}
+
 // Suffix adds an expression to the end of the query
 func (b SelectBuilder) Suffix(sql string, args ...interface{}) SelectBuilder {
 	return builder.Append(b, "Suffixes", Expr(sql, args...)).(SelectBuilder)
 }
 
+// countQueryAlias is the alias of the subquery counted by CountQuery.
+const countQueryAlias = "count_query"
+
+// CountQuery returns a query counting the rows the query would return without
+// its ORDER BY, LIMIT, OFFSET and pagination, e.g. to report the total number
+// of rows matching the filters of a paginated query. Locking clauses and
+// suffixes, which typically hold clauses such as FOR UPDATE, are dropped as
+// well.
+//
+// A plain query is counted by replacing its columns with COUNT(*). A query
+// with DISTINCT or other options, GROUP BY or HAVING is counted as a subquery
+// instead, aliased as its Dialect requires:
+//   SELECT COUNT(*) FROM (SELECT DISTINCT a FROM t) AS count_query
+//
+// Columns are not inspected: a query with aggregate columns but no GROUP BY
+// must add one to be counted by group.
+func (b SelectBuilder) CountQuery() SelectBuilder {
+	data := builder.GetStruct(b).(selectData)
+
+	count := b
+	for _, field := range []string{"OrderByParts", "Limit", "Offset", "LimitRowNum", "Page", "CountAll", "LockMode", "LockOf", "LockWait", "Suffixes"} {
+		count = builder.Delete(count, field).(SelectBuilder)
+	}
+
+	if len(data.Options) == 0 && len(data.GroupBys) == 0 && len(data.HavingParts) == 0 {
+		return builder.Set(count, "Columns", []Sqlizer{Expr("COUNT(*)")}).(SelectBuilder)
+	}
+
+	// The WITH clause and prefixes belong at the start of the statement,
+	// not inside the counted subquery.
+	inner := builder.Delete(builder.Delete(count, "Prefixes"), "CTEs").(SelectBuilder)
+	for _, field := range []string{"Options", "Columns", "Joins", "WhereParts", "WherePartsEscapeEmptyParams", "GroupBys", "HavingParts", "Windows"} {
+		count = builder.Delete(count, field).(SelectBuilder)
+	}
+	count = builder.Set(count, "Columns", []Sqlizer{Expr("COUNT(*)")}).(SelectBuilder)
+	return builder.Set(count, "From", derivedTable{query: inner, alias: countQueryAlias}).(SelectBuilder)
+}
+
 // For oracle only
 // Limit sets a LIMIT clause on the query.
+//
+// The query is paginated on the row numbers of a ROW_NUMBER() column named
+// rnum, unless it already projects an rnum column, e.g. "rownum as rnum",
+// whose values are then used instead.
 func (b SelectBuilder) LimitRowNum(limit uint64) SelectBuilder {
 	return builder.Set(b, "LimitRowNum", fmt.Sprintf("%d", limit)).(SelectBuilder)
 }
@@ -451,6 +806,10 @@ func (b SelectBuilder) Page(page uint64) SelectBuilder {
 	return builder.Set(b, "Page", fmt.Sprintf("%d", page)).(SelectBuilder)
 }
 
+// CountAll wraps the query in SELECT COUNT(*) FROM (...).
+//
+// The wrapped query keeps its ORDER BY and pagination and is not aliased,
+// which only Oracle accepts; see CountQuery for a portable count query.
 func (b SelectBuilder) CountAll(flag bool) SelectBuilder {
 	return builder.Set(b, "CountAll", flag).(SelectBuilder)
 }
@@ -88,6 +88,93 @@ func TestSelectPageLimitForOracle(t *testing.T) {
 	assert.Equal(t, expectedArgs, args)
 }
 
+func TestSelectPageOrderedForOracle(t *testing.T) {
+	b := Select("id", "name").
+		From("users").
+		Where("active = ?", 1).
+		OrderByClause("name = ? DESC", "moe").
+		OrderBy("id").
+		LimitRowNum(10).Page(3)
+
+	sql, args, err := b.PlaceholderFormat(Colon).ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT * " +
+		"FROM (SELECT id, name, ROW_NUMBER() OVER (ORDER BY name = :1 DESC, id) AS rnum " +
+		"FROM users WHERE active = :2) " +
+		"WHERE rnum > 20 AND rnum <= 30 ORDER BY rnum"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"moe", 1}, args)
+
+	sql, args, err = b.Dialect(Oracle).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM users WHERE active = :1 ORDER BY name = :2 DESC, id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", sql)
+	assert.Equal(t, []interface{}{1, "moe"}, args)
+}
+
+func TestSelectPageDistinctForOracle(t *testing.T) {
+	sql, _, err := Select("name").Distinct().From("users").OrderBy("name").LimitRowNum(5).Page(2).ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT * " +
+		"FROM (SELECT q.*, ROW_NUMBER() OVER (ORDER BY name) AS rnum FROM (SELECT DISTINCT name FROM users) q) " +
+		"WHERE rnum > 5 AND rnum <= 10 ORDER BY rnum"
+	assert.Equal(t, expectedSql, sql)
+}
+
+func TestSelectPageArgsForOracle(t *testing.T) {
+	sql, args, err := Select("name").
+		Distinct().
+		From("users").
+		Where("x = ?", "W").
+		OrderByClause("name = ? DESC", "O").
+		LimitRowNum(5).Page(2).
+		PlaceholderFormat(Colon).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT * " +
+		"FROM (SELECT q.*, ROW_NUMBER() OVER (ORDER BY name = :1 DESC) AS rnum " +
+		"FROM (SELECT DISTINCT name FROM users WHERE x = :2) q) " +
+		"WHERE rnum > 5 AND rnum <= 10 ORDER BY rnum"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"O", "W"}, args)
+}
+
+func TestSelectPageOwnRowNumberForOracle(t *testing.T) {
+	sql, args, err := Select("id", "rownum AS rnum").
+		From("users").
+		Where("active = ?", 1).
+		OrderBy("id").
+		LimitRowNum(10).Page(3).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT * " +
+		"FROM (SELECT id, rownum AS rnum FROM users WHERE active = ? ORDER BY id) " +
+		"WHERE rnum >= 21 AND rnum < 31"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	assert.True(t, projectsRowNumber(`a, f(rnum, b) AS "RNUM"`))
+	assert.True(t, projectsRowNumber("t.rnum"))
+	assert.False(t, projectsRowNumber("a, f(b, rnum)"))
+}
+
+func TestSelectPageLimitForOracleErr(t *testing.T) {
+	_, _, err := Select("a").From("t").LimitRowNum(0).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("a").From("t").LimitRowNum(10).Page(0).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("a").From("t").Page(2).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("a").From("t").LimitRowNum(10).Limit(10).ToSql()
+	assert.Error(t, err)
+}
+
 func TestWhereEscapeEmptyParams(t *testing.T) {
 	//subQ := Select("c").From("d").Where(Eq{"i": 0})
 	subQ := Select("t1.A, t1.B, t2.C, rownum as rnum").
@@ -333,3 +420,143 @@ func TestSelectWithEmptyStringWhereClause(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "SELECT * FROM users", sql)
 }
+
+func TestSelectBuilderWith(t *testing.T) {
+	recent := Select("id", "user_id").From("orders").Where("created_at > ?", 1)
+	totals := Select("user_id", "COUNT(*) AS n").From("recent").GroupBy("user_id").Having("COUNT(*) > ?", 2)
+	b := Select("u.name", "t.n").
+		With("recent", recent).
+		With("totals(user_id, n)", totals).
+		From("users u").
+		Join("totals t ON t.user_id = u.id").
+		Where(Eq{"u.active": true})
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "WITH recent AS (SELECT id, user_id FROM orders WHERE created_at > ?), " +
+		"totals(user_id, n) AS (SELECT user_id, COUNT(*) AS n FROM recent GROUP BY user_id HAVING COUNT(*) > ?) " +
+		"SELECT u.name, t.n FROM users u JOIN totals t ON t.user_id = u.id WHERE u.active = ?"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1, 2, true}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderWithDollarPlaceholders(t *testing.T) {
+	recent := Select("id").From("orders").Where("created_at > ?", 1).PlaceholderFormat(Dollar)
+	b := Select("*").
+		Prefix("/* report */").
+		With("recent", recent).
+		From("recent").
+		Where("id > ?", 2)
+
+	sql, args, err := b.PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"/* report */ WITH recent AS (SELECT id FROM orders WHERE created_at > $1) SELECT * FROM recent WHERE id > $2",
+		sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, _, err = b.PlaceholderFormat(Colon).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"/* report */ WITH recent AS (SELECT id FROM orders WHERE created_at > :1) SELECT * FROM recent WHERE id > :2",
+		sql)
+}
+
+func TestSelectBuilderWithRecursive(t *testing.T) {
+	tree := ConcatExpr(
+		Select("id", "parent_id").From("nodes").Where(Eq{"id": 1}),
+		" UNION ALL ",
+		Select("n.id", "n.parent_id").From("nodes n").Join("tree ON n.parent_id = tree.id").Where("n.depth < ?", 5),
+	)
+	b := Select("id").
+		With("roots", Select("id").From("nodes").Where(Eq{"parent_id": nil})).
+		WithRecursive("tree(id, parent_id)", tree).
+		From("tree").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "WITH RECURSIVE roots AS (SELECT id FROM nodes WHERE parent_id IS NULL), " +
+		"tree(id, parent_id) AS (SELECT id, parent_id FROM nodes WHERE id = $1 UNION ALL " +
+		"SELECT n.id, n.parent_id FROM nodes n JOIN tree ON n.parent_id = tree.id WHERE n.depth < $2) " +
+		"SELECT id FROM tree"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 5}, args)
+}
+
+func TestSelectBuilderWithErr(t *testing.T) {
+	_, _, err := Select("*").With("", Select("1")).From("x").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("*").With("x", Select()).From("x").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderCountQuery(t *testing.T) {
+	b := Select("id", "name").
+		From("users").
+		Join("groups g ON g.id = users.group_id").
+		Where(Eq{"g.name": "admins"}).
+		OrderBy("name").
+		Limit(10).
+		Offset(20).
+		Suffix("FOR UPDATE")
+
+	sql, args, err := b.CountQuery().PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM users JOIN groups g ON g.id = users.group_id WHERE g.name = $1", sql)
+	assert.Equal(t, []interface{}{"admins"}, args)
+
+	// The original query is left untouched.
+	sql, _, err = b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM users JOIN groups g ON g.id = users.group_id WHERE g.name = ? ORDER BY name LIMIT 10 OFFSET 20 FOR UPDATE", sql)
+}
+
+func TestSelectBuilderCountQueryDistinct(t *testing.T) {
+	b := Select("a").Distinct().From("t").Where("b > ?", 1).OrderBy("a").Limit(5)
+
+	sql, args, err := b.Dialect(PostgreSQL).CountQuery().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT DISTINCT a FROM t WHERE b > $1) AS count_query", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _, err = b.Dialect(Oracle).CountQuery().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT DISTINCT a FROM t WHERE b > :1) count_query", sql)
+}
+
+func TestSelectBuilderCountQueryGroupBy(t *testing.T) {
+	b := Select("a", "COUNT(*)").
+		Prefix("/* list */").
+		With("w", Select("a").From("t").Where("c = ?", 2)).
+		From("w").
+		GroupBy("a").
+		Having("COUNT(*) > ?", 1).
+		LimitRowNum(10).Page(2)
+
+	sql, args, err := b.CountQuery().PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	expectedSql := "/* list */ WITH w AS (SELECT a FROM t WHERE c = $1) " +
+		"SELECT COUNT(*) FROM (SELECT a, COUNT(*) FROM w GROUP BY a HAVING COUNT(*) > $2) AS count_query"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{2, 1}, args)
+}
+
+func TestSelectBuilderCountQueryWindow(t *testing.T) {
+	b := Select("DISTINCT a", "SUM(b) OVER w").
+		From("t").
+		GroupBy("a", "b").
+		Window("w", WindowSpec().PartitionBy("a").OrderByClause("b > ?", 1))
+
+	sql, args, err := b.CountQuery().PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	expectedSql := "SELECT COUNT(*) FROM " +
+		"(SELECT DISTINCT a, SUM(b) OVER w FROM t GROUP BY a, b WINDOW w AS (PARTITION BY a ORDER BY b > $1)) AS count_query"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
@@ -0,0 +1,111 @@
+package squirrel
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SortFields whitelists the fields a sort specification can sort by, mapping
+// their public names to the columns, or SQL expressions, to sort by. An empty
+// column sorts by the field name itself. Expressions, e.g. LOWER(name), are
+// trusted as they are by StatementBuilderType.StrictIdents.
+type SortFields map[string]string
+
+var (
+	// UnknownSortField is wrapped by the SortErrors of fields missing from
+	// SortFields.
+	UnknownSortField = errors.New("unknown sort field")
+
+	// InvalidSort is wrapped by the SortErrors of malformed sort
+	// specifications.
+	InvalidSort = errors.New("invalid sort")
+)
+
+// SortError is the type of the errors returned by SortFields.Parse.
+type SortError struct {
+	// Field is the part of the sort specification in error.
+	Field string
+	// Err is UnknownSortField or InvalidSort.
+	Err error
+}
+
+func (e *SortError) Error() string {
+	return fmt.Sprintf("%v %q", e.Err, e.Field)
+}
+
+func (e *SortError) Unwrap() error {
+	return e.Err
+}
+
+// Parse parses a sort specification, e.g. the value of a ?sort= query
+// parameter, into the SortColumns of the fields it lists, for OrderBySort or
+// Seek.
+//
+// The specification is a comma-separated list of fields, sorted in ascending
+// order unless prefixed with "-", and optionally followed by the modifiers
+// ":asc", ":desc", ":nulls_first" or ":nulls_last":
+//   -created_at,name:nulls_last
+// An empty specification returns no columns. Fields missing from f return a
+// *SortError wrapping UnknownSortField, other errors wrap InvalidSort.
+func (f SortFields) Parse(spec string) ([]SortColumn, error) {
+	if len(strings.TrimSpace(spec)) == 0 {
+		return nil, nil
+	}
+
+	var columns []SortColumn
+	seen := map[string]bool{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		invalid := &SortError{Field: item, Err: InvalidSort}
+
+		var c SortColumn
+		var direction bool
+		switch {
+		case strings.HasPrefix(item, "-"):
+			c.Desc, direction = true, true
+			item = item[1:]
+		case strings.HasPrefix(item, "+"):
+			direction = true
+			item = item[1:]
+		}
+
+		parts := strings.Split(item, ":")
+		name := parts[0]
+		for _, modifier := range parts[1:] {
+			switch strings.ToLower(modifier) {
+			case "asc", "desc":
+				if direction {
+					return nil, invalid
+				}
+				c.Desc, direction = strings.EqualFold(modifier, "desc"), true
+			case "nulls_first", "nulls_last":
+				if c.Nulls != NullsDefault {
+					return nil, invalid
+				}
+				c.Nulls = NullsFirst
+				if strings.EqualFold(modifier, "nulls_last") {
+					c.Nulls = NullsLast
+				}
+			default:
+				return nil, invalid
+			}
+		}
+
+		if len(name) == 0 || seen[name] {
+			return nil, invalid
+		}
+		column, ok := f[name]
+		if !ok {
+			return nil, &SortError{Field: name, Err: UnknownSortField}
+		}
+		if len(column) == 0 {
+			column = name
+		}
+		seen[name] = true
+		c.Column = column
+		c.trusted = identChecker{}.check(column, identName) != nil
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
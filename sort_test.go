@@ -0,0 +1,78 @@
+package squirrel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSortFields = SortFields{
+	"name":    "u.name",
+	"created": "u.created_at",
+	"id":      "",
+}
+
+func TestSortFieldsParse(t *testing.T) {
+	columns, err := testSortFields.Parse("-created, name:nulls_last,+id")
+	assert.NoError(t, err)
+	assert.Equal(t, []SortColumn{
+		{Column: "u.created_at", Desc: true},
+		{Column: "u.name", Nulls: NullsLast},
+		{Column: "id"},
+	}, columns)
+
+	columns, err = testSortFields.Parse("name:DESC:nulls_first")
+	assert.NoError(t, err)
+	assert.Equal(t, []SortColumn{{Column: "u.name", Desc: true, Nulls: NullsFirst}}, columns)
+
+	columns, err = testSortFields.Parse(" ")
+	assert.NoError(t, err)
+	assert.Empty(t, columns)
+
+	sql, _, err := Select("id").From("users u").OrderBySort(columns...).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users u", sql)
+}
+
+func TestSortFieldsParseErrors(t *testing.T) {
+	_, err := testSortFields.Parse("name,password")
+	assert.EqualError(t, err, `unknown sort field "password"`)
+	assert.True(t, errors.Is(err, UnknownSortField))
+	var sortErr *SortError
+	if assert.True(t, errors.As(err, &sortErr)) {
+		assert.Equal(t, "password", sortErr.Field)
+	}
+
+	for _, spec := range []string{
+		"name,",
+		"-",
+		"name,-name",
+		"-name:asc",
+		"name:asc:desc",
+		"name:nulls_first:nulls_last",
+		"name:random",
+		"name desc",
+	} {
+		_, err := testSortFields.Parse(spec)
+		assert.True(t, errors.Is(err, InvalidSort) || errors.Is(err, UnknownSortField), spec)
+	}
+	_, err = testSortFields.Parse("name:asc:desc")
+	assert.EqualError(t, err, `invalid sort "name:asc:desc"`)
+}
+
+func TestSortFieldsSeek(t *testing.T) {
+	columns, err := testSortFields.Parse("-created,id")
+	assert.NoError(t, err)
+
+	sql, args, err := Select("id").From("users u").
+		Seek(columns, "2020-01-01", 42).
+		Limit(10).
+		Dialect(PostgreSQL).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users u "+
+		"WHERE (u.created_at < $1 OR (u.created_at = $2 AND id > $3)) "+
+		"ORDER BY u.created_at DESC, id ASC LIMIT 10", sql)
+	assert.Equal(t, []interface{}{"2020-01-01", "2020-01-01", 42}, args)
+}
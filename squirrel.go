@@ -7,8 +7,8 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/lann/builder"
 )
@@ -79,26 +79,77 @@ func (r *stdsqlRunner) QueryRow(query string, args ...interface{}) RowScanner {
 }
 
 func setRunWith(b interface{}, baseRunner BaseRunner) interface{} {
-	var runner Runner
+	// Runners that are neither Runners nor database/sql values are kept as
+	// they are, so that running them reports what they cannot do.
+	runner := baseRunner
 	switch r := baseRunner.(type) {
 	case Runner:
 		runner = r
 	case stdsql:
 		runner = &stdsqlRunner{r}
-
 	}
 	return builder.Set(b, "RunWith", runner)
 }
 
+// Errors returned by the Exec, Query, QueryRow and Scan methods of the
+// builders, and their Context versions, are either:
+//   - a *RunnerError, such as RunnerNotSet, when the Runner set by RunWith
+//     cannot run the statement,
+//   - a *BuildError, when the statement cannot be built, e.g. ToSql fails,
+//   - or an error returned by the Runner, i.e. by the database driver.
+// The first two are configuration errors, which retrying cannot fix.
+
+// RunnerError is the type of the errors returned when a builder cannot run a
+// statement with the Runner set by RunWith.
+type RunnerError struct {
+	msg string
+}
+
+func (e *RunnerError) Error() string {
+	return e.msg
+}
+
+// BuildError is the type of the errors returned when a builder cannot build
+// the statement it is asked to run.
+type BuildError struct {
+	Err error
+}
+
+func (e *BuildError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error that prevented the statement from being built.
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// IsConfigError reports whether err, or an error it wraps, is a *RunnerError
+// or a *BuildError rather than an error returned by the database driver.
+func IsConfigError(err error) bool {
+	var runnerErr *RunnerError
+	var buildErr *BuildError
+	return errors.As(err, &runnerErr) || errors.As(err, &buildErr)
+}
+
+// toSqlForRun calls ToSql on s, wrapping its error in a *BuildError.
+func toSqlForRun(s Sqlizer) (string, []interface{}, error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return "", nil, &BuildError{Err: err}
+	}
+	return query, args, nil
+}
+
 // RunnerNotSet is returned by methods that need a Runner if it isn't set.
-var RunnerNotSet = fmt.Errorf("cannot run; no Runner set (RunWith)")
+var RunnerNotSet error = &RunnerError{"cannot run; no Runner set (RunWith)"}
 
 // RunnerNotQueryRunner is returned by QueryRow if the RunWith value doesn't implement QueryRower.
-var RunnerNotQueryRunner = fmt.Errorf("cannot QueryRow; Runner is not a QueryRower")
+var RunnerNotQueryRunner error = &RunnerError{"cannot QueryRow; Runner is not a QueryRower"}
 
 // ExecWith Execs the SQL returned by s with db.
 func ExecWith(db Execer, s Sqlizer) (res sql.Result, err error) {
-	query, args, err := s.ToSql()
+	query, args, err := toSqlForRun(s)
 	if err != nil {
 		return
 	}
@@ -107,7 +158,7 @@ func ExecWith(db Execer, s Sqlizer) (res sql.Result, err error) {
 
 // QueryWith Querys the SQL returned by s with db.
 func QueryWith(db Queryer, s Sqlizer) (rows *sql.Rows, err error) {
-	query, args, err := s.ToSql()
+	query, args, err := toSqlForRun(s)
 	if err != nil {
 		return
 	}
@@ -116,8 +167,40 @@ func QueryWith(db Queryer, s Sqlizer) (rows *sql.Rows, err error) {
 
 // QueryRowWith QueryRows the SQL returned by s with db.
 func QueryRowWith(db QueryRower, s Sqlizer) RowScanner {
-	query, args, err := s.ToSql()
-	return &Row{RowScanner: db.QueryRow(query, args...), err: err}
+	query, args, err := toSqlForRun(s)
+	if err != nil {
+		return &Row{err: err}
+	}
+	return &Row{RowScanner: db.QueryRow(query, args...)}
+}
+
+// execWithRunner Execs the SQL returned by s with the Runner set by RunWith.
+func execWithRunner(runner BaseRunner, s Sqlizer) (sql.Result, error) {
+	if runner == nil {
+		return nil, RunnerNotSet
+	}
+	return ExecWith(runner, s)
+}
+
+// queryWithRunner Querys the SQL returned by s with the Runner set by RunWith.
+func queryWithRunner(runner BaseRunner, s Sqlizer) (*sql.Rows, error) {
+	if runner == nil {
+		return nil, RunnerNotSet
+	}
+	return QueryWith(runner, s)
+}
+
+// queryRowWithRunner QueryRows the SQL returned by s with the Runner set by
+// RunWith.
+func queryRowWithRunner(runner BaseRunner, s Sqlizer) RowScanner {
+	if runner == nil {
+		return &Row{err: RunnerNotSet}
+	}
+	queryRower, ok := runner.(QueryRower)
+	if !ok {
+		return &Row{err: RunnerNotQueryRunner}
+	}
+	return QueryRowWith(queryRower, s)
 }
 
 // DebugSqlizer calls ToSql on s and shows the approximate SQL to be executed
@@ -135,47 +218,34 @@ func DebugSqlizer(s Sqlizer) string {
 		return fmt.Sprintf("[ToSql error: %s]", err)
 	}
 
-	var placeholder string
-	downCast, ok := s.(placeholderDebugger)
-	if !ok {
-		placeholder = "?"
-	} else {
-		placeholder = downCast.debugPlaceholder()
+	// Placeholders are numbered after a prefix of one or more characters,
+	// e.g. $1 or @p1, unless they are question marks.
+	prefix := ""
+	if downCast, ok := s.(placeholderDebugger); ok && downCast.debugPlaceholder() != "?" {
+		prefix = downCast.debugPlaceholder()
 	}
-	// TODO: dedupe this with placeholder.go
-	buf := &bytes.Buffer{}
-	i := 0
-	for {
-		p := strings.Index(sql, placeholder)
-		if p == -1 {
-			break
-		}
-		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
-			buf.WriteString(sql[:p])
-			buf.WriteString("?")
-			if len(sql[p:]) == 1 {
-				break
-			}
-			sql = sql[p+2:]
-		} else {
-			if i+1 > len(args) {
-				return fmt.Sprintf(
-					"[DebugSqlizer error: too many placeholders in %#v for %d args]",
-					sql, len(args))
+	named := make(map[string]bool)
+	debug, n, err := rewriteParams(sql, prefix, backslashAuto, func(buf *bytes.Buffer, i int, name string) error {
+		if len(name) > 0 {
+			if i = namedArgIndex(args, name); i < 0 {
+				buf.WriteString("@" + name)
+				return nil
 			}
-			buf.WriteString(sql[:p])
-			fmt.Fprintf(buf, "'%v'", args[i])
-			// advance our sql string "cursor" beyond the arg we placed
-			sql = sql[p+1:]
-			i++
+			named[name] = true
 		}
+		if i < 0 || i >= len(args) {
+			return fmt.Errorf("too many placeholders in %#v for %d args", sql, len(args))
+		}
+		fmt.Fprintf(buf, "'%v'", namedArgValue(args[i]))
+		return nil
+	})
+	if err != nil {
+		return fmt.Sprintf("[DebugSqlizer error: %s]", err)
 	}
-	if i < len(args) {
+	if n+len(named) < len(args) {
 		return fmt.Sprintf(
 			"[DebugSqlizer error: not enough placeholders in %#v for %d args]",
 			sql, len(args))
 	}
-	// "append" any remaning sql that won't need interpolating
-	buf.WriteString(sql)
-	return buf.String()
+	return debug
 }
@@ -5,11 +5,10 @@ package squirrel
 import (
 	"context"
 	"database/sql"
-	"errors"
 )
 
 // NoContextSupport is returned if a db doesn't support Context.
-var NoContextSupport = errors.New("DB does not support Context")
+var NoContextSupport error = &RunnerError{"DB does not support Context"}
 
 // ExecerContext is the interface that wraps the ExecContext method.
 //
@@ -38,7 +37,7 @@ func (r *stdsqlRunner) QueryRowContext(ctx context.Context, query string, args .
 
 // ExecContextWith ExecContexts the SQL returned by s with db.
 func ExecContextWith(ctx context.Context, db ExecerContext, s Sqlizer) (res sql.Result, err error) {
-	query, args, err := s.ToSql()
+	query, args, err := toSqlForRun(s)
 	if err != nil {
 		return
 	}
@@ -47,7 +46,7 @@ func ExecContextWith(ctx context.Context, db ExecerContext, s Sqlizer) (res sql.
 
 // QueryContextWith QueryContexts the SQL returned by s with db.
 func QueryContextWith(ctx context.Context, db QueryerContext, s Sqlizer) (rows *sql.Rows, err error) {
-	query, args, err := s.ToSql()
+	query, args, err := toSqlForRun(s)
 	if err != nil {
 		return
 	}
@@ -56,6 +55,51 @@ func QueryContextWith(ctx context.Context, db QueryerContext, s Sqlizer) (rows *
 
 // QueryRowContextWith QueryRowContexts the SQL returned by s with db.
 func QueryRowContextWith(ctx context.Context, db QueryRowerContext, s Sqlizer) RowScanner {
-	query, args, err := s.ToSql()
-	return &Row{RowScanner: db.QueryRowContext(ctx, query, args...), err: err}
+	query, args, err := toSqlForRun(s)
+	if err != nil {
+		return &Row{err: err}
+	}
+	return &Row{RowScanner: db.QueryRowContext(ctx, query, args...)}
+}
+
+// execContextWithRunner ExecContexts the SQL returned by s with the Runner set
+// by RunWith.
+func execContextWithRunner(ctx context.Context, runner BaseRunner, s Sqlizer) (sql.Result, error) {
+	if runner == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := runner.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	return ExecContextWith(ctx, ctxRunner, s)
+}
+
+// queryContextWithRunner QueryContexts the SQL returned by s with the Runner
+// set by RunWith.
+func queryContextWithRunner(ctx context.Context, runner BaseRunner, s Sqlizer) (*sql.Rows, error) {
+	if runner == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := runner.(QueryerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	return QueryContextWith(ctx, ctxRunner, s)
+}
+
+// queryRowContextWithRunner QueryRowContexts the SQL returned by s with the
+// Runner set by RunWith.
+func queryRowContextWithRunner(ctx context.Context, runner BaseRunner, s Sqlizer) RowScanner {
+	if runner == nil {
+		return &Row{err: RunnerNotSet}
+	}
+	queryRower, ok := runner.(QueryRowerContext)
+	if !ok {
+		if _, ok := runner.(QueryerContext); !ok {
+			return &Row{err: RunnerNotQueryRunner}
+		}
+		return &Row{err: NoContextSupport}
+	}
+	return QueryRowContextWith(ctx, queryRower, s)
 }
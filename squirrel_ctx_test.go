@@ -53,3 +53,30 @@ func TestQueryRowContextWith(t *testing.T) {
 	QueryRowContextWith(ctx, db, sqlizer)
 	assert.Equal(t, sqlStr, db.LastQueryRowSql)
 }
+
+func TestRunnerCapabilityErrors(t *testing.T) {
+	type baseRunner struct {
+		Execer
+		Queryer
+	}
+	runner := baseRunner{&DBStub{}, &DBStub{}}
+
+	builders := []interface {
+		Scan(...interface{}) error
+		ScanContext(context.Context, ...interface{}) error
+		ExecContext(context.Context) (sql.Result, error)
+	}{
+		Select("a").From("t").RunWith(runner),
+		Insert("t").Values(1).RunWith(runner),
+		Update("t").Set("a", 1).RunWith(runner),
+		Delete("t").RunWith(runner),
+		Union(Select("a").From("t"), Select("a").From("u")).RunWith(runner),
+	}
+	for _, b := range builders {
+		assert.Equal(t, RunnerNotQueryRunner, b.Scan())
+		assert.Equal(t, RunnerNotQueryRunner, b.ScanContext(context.Background()))
+		_, err := b.ExecContext(context.Background())
+		assert.Equal(t, NoContextSupport, err)
+		assert.True(t, IsConfigError(err))
+	}
+}
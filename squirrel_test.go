@@ -86,7 +86,25 @@ func TestWithToSqlErr(t *testing.T) {
 	assert.Error(t, err)
 }
 
-var testDebugUpdateSQL = Update("table").SetMap(Eq{"x": 1, "y": "val"})
+func TestRunErrorKinds(t *testing.T) {
+	_, err := Delete("").RunWith(&DBStub{}).Exec()
+	assert.IsType(t, &BuildError{}, err)
+	assert.True(t, IsConfigError(err))
+	assert.Equal(t, "delete statements must specify a From table", err.Error())
+
+	err = Update("t").SetStruct(42).RunWith(&DBStub{}).Scan()
+	assert.IsType(t, &BuildError{}, err)
+	assert.True(t, IsConfigError(fmt.Errorf("wrapped: %w", err)))
+
+	_, err = Select("a").Exec()
+	assert.Equal(t, RunnerNotSet, err)
+	assert.True(t, IsConfigError(err))
+
+	assert.False(t, IsConfigError(StubError))
+	assert.False(t, IsConfigError(nil))
+}
+
+var testDebugUpdateSQL = Update("table").SetMap(Eq{"x": 1, "y": "val"}).AllRows()
 var expectedDebugUpateSQL = "UPDATE table SET x = '1', y = 'val'"
 
 func TestDebugSqlizerUpdateColon(t *testing.T) {
@@ -170,6 +188,72 @@ func TestDebugSqlizer(t *testing.T) {
 	assert.Equal(t, expectedDebug, DebugSqlizer(sqlizer))
 }
 
+func TestDebugSqlizerBackslashEscapes(t *testing.T) {
+	sqlizer := Expr("x = ? AND y = 'it\\'s' AND z = ?", 1, 2)
+	expectedDebug := "x = '1' AND y = 'it\\'s' AND z = '2'"
+	assert.Equal(t, expectedDebug, DebugSqlizer(sqlizer))
+}
+
+func TestDebugSqlizerLiterals(t *testing.T) {
+	sqlizer := Expr("x = ? AND y = ? AND z = '?' AND data ? 'k'", 1, "text")
+	expectedDebug := "x = '1' AND y = 'text' AND z = '?' AND data ? 'k'"
+	assert.Equal(t, expectedDebug, DebugSqlizer(sqlizer))
+}
+
+func TestDebugSqlizerJsonbOperators(t *testing.T) {
+	sqlizer := Expr("data ? 'k' AND data ?| array['a'] AND data ?& array['a', ?] AND id = ?", "b", 1)
+	expectedDebug := "data ? 'k' AND data ?| array['a'] AND data ?& array['a', 'b'] AND id = '1'"
+	assert.Equal(t, expectedDebug, DebugSqlizer(sqlizer))
+
+	b := Select("id").From("nodes").Where(sqlizer)
+	for _, f := range []PlaceholderFormat{Question, Dollar, Colon} {
+		assert.Equal(t, "SELECT id FROM nodes WHERE "+expectedDebug, DebugSqlizer(b.PlaceholderFormat(f)))
+	}
+}
+
+func TestDebugSqlizerPlaceholderFormats(t *testing.T) {
+	b := Select("a").From("t").Where("b = ? AND c = '$1 @p1'", 1).Where(Eq{"d": []int{2, 3}})
+	expected := "SELECT a FROM t WHERE b = '1' AND c = '$1 @p1' AND d IN ('2','3')"
+	for _, f := range []PlaceholderFormat{Question, Dollar, Colon, AtP, NamedArgs("@p"), NamedArgs(":arg")} {
+		assert.Equal(t, expected, DebugSqlizer(b.PlaceholderFormat(f)))
+	}
+
+	b = Select("a").From("t").Where("b = ?", 1).Suffix("LIMIT ?", 10).PlaceholderFormat(AtP)
+	for i := 0; i < 10; i++ {
+		b = b.Where("c = ?", i)
+	}
+	assert.Equal(t,
+		"SELECT a FROM t WHERE b = '1' AND c = '0' AND c = '1' AND c = '2' AND c = '3' AND c = '4' "+
+			"AND c = '5' AND c = '6' AND c = '7' AND c = '8' AND c = '9' LIMIT '10'",
+		DebugSqlizer(b))
+}
+
+// atpSqlizer is a Sqlizer of a statement with AtP placeholders.
+type atpSqlizer struct {
+	sql  string
+	args []interface{}
+}
+
+func (s atpSqlizer) ToSql() (string, []interface{}, error) {
+	return s.sql, s.args, nil
+}
+
+func (atpSqlizer) debugPlaceholder() string {
+	return "@p"
+}
+
+func TestDebugSqlizerNamedArgs(t *testing.T) {
+	sqlizer := atpSqlizer{
+		sql:  "DECLARE @n INT; SELECT a FROM t WHERE b = @b AND c = @p2 AND d = @b",
+		args: []interface{}{sql.Named("b", "x"), 1},
+	}
+	expectedDebug := "DECLARE @n INT; SELECT a FROM t WHERE b = 'x' AND c = '1' AND d = 'x'"
+	assert.Equal(t, expectedDebug, DebugSqlizer(sqlizer))
+
+	sqlizer.args = append(sqlizer.args, sql.Named("e", 2))
+	assert.True(t, strings.HasPrefix(DebugSqlizer(sqlizer), "[DebugSqlizer error: "))
+}
+
 func TestDebugSqlizerErrors(t *testing.T) {
 	errorMsg := DebugSqlizer(Expr("x = ?", 1, 2)) // Not enough placeholders
 	assert.True(t, strings.HasPrefix(errorMsg, "[DebugSqlizer error: "))
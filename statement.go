@@ -5,11 +5,42 @@ import "github.com/lann/builder"
 // StatementBuilderType is the type of StatementBuilder.
 type StatementBuilderType builder.Builder
 
+// statementOptions are the options StatementBuilder sets for every kind of
+// statement, embedded in the data of each builder.
+type statementOptions struct {
+	StrictIdents bool
+	CheckParams  bool
+}
+
 // Select returns a SelectBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Select(columns ...string) SelectBuilder {
 	return SelectBuilder(b).Columns(columns...)
 }
 
+// Union returns a CompoundSelectBuilder combining selects with UNION for this
+// StatementBuilderType.
+func (b StatementBuilderType) Union(selects ...SelectBuilder) CompoundSelectBuilder {
+	return CompoundSelectBuilder(b).Union(selects...)
+}
+
+// UnionAll returns a CompoundSelectBuilder combining selects with UNION ALL for
+// this StatementBuilderType.
+func (b StatementBuilderType) UnionAll(selects ...SelectBuilder) CompoundSelectBuilder {
+	return CompoundSelectBuilder(b).UnionAll(selects...)
+}
+
+// Intersect returns a CompoundSelectBuilder combining selects with INTERSECT
+// for this StatementBuilderType.
+func (b StatementBuilderType) Intersect(selects ...SelectBuilder) CompoundSelectBuilder {
+	return CompoundSelectBuilder(b).Intersect(selects...)
+}
+
+// Except returns a CompoundSelectBuilder combining selects with EXCEPT for this
+// StatementBuilderType.
+func (b StatementBuilderType) Except(selects ...SelectBuilder) CompoundSelectBuilder {
+	return CompoundSelectBuilder(b).Except(selects...)
+}
+
 // Insert returns a InsertBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Insert(into string) InsertBuilder {
 	return InsertBuilder(b).Into(into)
@@ -23,12 +54,20 @@ func (b StatementBuilderType) Replace(into string) InsertBuilder {
 
 // Update returns a UpdateBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Update(table string) UpdateBuilder {
-	return UpdateBuilder(b).Table(table)
+	ub := UpdateBuilder(b).Table(table)
+	if b.unsafe() {
+		ub = ub.AllRows()
+	}
+	return ub
 }
 
 // Delete returns a DeleteBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Delete(from string) DeleteBuilder {
-	return DeleteBuilder(b).From(from)
+	db := DeleteBuilder(b).From(from)
+	if b.unsafe() {
+		db = db.AllRows()
+	}
+	return db
 }
 
 // PlaceholderFormat sets the PlaceholderFormat field for any child builders.
@@ -36,11 +75,56 @@ func (b StatementBuilderType) PlaceholderFormat(f PlaceholderFormat) StatementBu
 	return builder.Set(b, "PlaceholderFormat", f).(StatementBuilderType)
 }
 
+// Dialect sets the Dialect field, along with the PlaceholderFormat field, for
+// any child builders.
+func (b StatementBuilderType) Dialect(d Dialect) StatementBuilderType {
+	return setDialect(b, d).(StatementBuilderType)
+}
+
 // RunWith sets the RunWith field for any child builders.
 func (b StatementBuilderType) RunWith(runner BaseRunner) StatementBuilderType {
 	return setRunWith(b, runner).(StatementBuilderType)
 }
 
+// Unsafe allows the child UpdateBuilders and DeleteBuilders to affect every
+// row of their table, as if AllRows was called on each of them.
+func (b StatementBuilderType) Unsafe() StatementBuilderType {
+	// Unexported values are left out of the structs of the builders, so only
+	// Update and Delete see it.
+	return builder.Set(b, "unsafe", true).(StatementBuilderType)
+}
+
+func (b StatementBuilderType) unsafe() bool {
+	unsafe, _ := builder.Get(b, "unsafe")
+	return unsafe == true
+}
+
+// StrictIdents makes the child builders check the identifiers of their
+// queries, returning an error wrapping InvalidIdentifier from ToSql for those
+// that are neither quoted as by the QuoteIdent method of the Dialect nor made
+// of the characters [A-Za-z0-9_.] only.
+//
+// Checked identifiers are the tables, columns and GROUP BY and ORDER BY
+// expressions given as strings, which may only be followed by an alias or by
+// ASC, DESC and NULLS FIRST or LAST, respectively, and the keys of Eq, Lt,
+// Like and the other maps of expressions. Expressions to use in their place,
+// such as COUNT(*), are given as Sqlizers, e.g. with Expr. The conditions of
+// Where, Having and joins given as strings are SQL expressions and are not
+// checked.
+func (b StatementBuilderType) StrictIdents() StatementBuilderType {
+	return builder.Set(b, "StrictIdents", true).(StatementBuilderType)
+}
+
+// CheckParams makes the ToSql methods of the child builders check that their
+// statements have a placeholder per argument, returning an error wrapping
+// PlaceholderMismatch that names the first offending clause otherwise, e.g.
+// "Where 2" or "Values row 3", and that they do not have more arguments than
+// the maximum of their Dialect, returning an error wrapping TooManyParams
+// otherwise.
+func (b StatementBuilderType) CheckParams() StatementBuilderType {
+	return builder.Set(b, "CheckParams", true).(StatementBuilderType)
+}
+
 // StatementBuilder is a parent builder for other builders, e.g. SelectBuilder.
 var StatementBuilder = StatementBuilderType(builder.EmptyBuilder).PlaceholderFormat(Question)
 
@@ -51,6 +135,31 @@ func Select(columns ...string) SelectBuilder {
 	return StatementBuilder.Select(columns...)
 }
 
+// Union returns a new CompoundSelectBuilder combining selects with UNION.
+//
+// Further selects may be combined with other operators, e.g.:
+//   Union(a, b).Except(c)
+func Union(selects ...SelectBuilder) CompoundSelectBuilder {
+	return StatementBuilder.Union(selects...)
+}
+
+// UnionAll returns a new CompoundSelectBuilder combining selects with
+// UNION ALL.
+func UnionAll(selects ...SelectBuilder) CompoundSelectBuilder {
+	return StatementBuilder.UnionAll(selects...)
+}
+
+// Intersect returns a new CompoundSelectBuilder combining selects with
+// INTERSECT.
+func Intersect(selects ...SelectBuilder) CompoundSelectBuilder {
+	return StatementBuilder.Intersect(selects...)
+}
+
+// Except returns a new CompoundSelectBuilder combining selects with EXCEPT.
+func Except(selects ...SelectBuilder) CompoundSelectBuilder {
+	return StatementBuilder.Except(selects...)
+}
+
 // Insert returns a new InsertBuilder with the given table name.
 //
 // See InsertBuilder.Into.
@@ -44,3 +44,20 @@ func TestRunWithTx(t *testing.T) {
 		builder.GetStruct(Delete("t").RunWith(tx))
 	}, "RunWith(*sql.Tx) should not panic")
 }
+
+func TestStatementBuilderUnsafe(t *testing.T) {
+	sb := StatementBuilder.Unsafe()
+	assert.NotPanics(t, func() {
+		builder.GetStruct(sb.Select("a"))
+		builder.GetStruct(sb.Insert("t"))
+		builder.GetStruct(sb.Union(Select("a")))
+	})
+
+	sql, _, err := sb.Update("t").Set("a", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ?", sql)
+
+	sql, _, err = sb.Delete("t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t", sql)
+}
@@ -0,0 +1,253 @@
+package squirrel
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// structTag is the struct tag mapping struct fields to columns, e.g.
+//   ID      int64  `db:"id,pk,omitempty"`
+//   Name    string `db:"name"`
+//   Created time.Time `db:"created_at,readonly"`
+// Its options are:
+//   omitempty  the column is not written when the field has its zero value,
+//              or is a driver.Valuer whose Value is nil
+//   readonly   the column is never written, e.g. for generated columns
+//   pk         the column is part of the primary key and is not updated
+// Fields tagged "-" and untagged fields are ignored, except for embedded
+// structs whose fields are mapped as if they were fields of the outer struct.
+// A tag without a column name maps the field to its lowercased name.
+const structTag = "db"
+
+// structField is a column mapped to a field of a struct by its db tag.
+type structField struct {
+	column    string
+	index     []int
+	omitEmpty bool
+	readOnly  bool
+	pk        bool
+}
+
+var structFieldsCache sync.Map // map[reflect.Type][]structField
+
+var (
+	valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	timeType   = reflect.TypeOf(time.Time{})
+)
+
+// structFields returns the columns mapped to the fields of the struct type t.
+func structFields(t reflect.Type) ([]structField, error) {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]structField), nil
+	}
+
+	var all []structField
+	depths := map[string][]int{}
+	collectStructFields(t, nil, &all, depths)
+
+	// As with Go's own field promotion, a shallower field hides deeper ones
+	// of the same name, but fields at the same depth are ambiguous.
+	var fields []structField
+	for _, f := range all {
+		minDepth, count := len(f.index), 0
+		for _, d := range depths[f.column] {
+			if d < minDepth {
+				minDepth, count = d, 0
+			}
+			if d == minDepth {
+				count++
+			}
+		}
+		if len(f.index) != minDepth {
+			continue
+		}
+		if count > 1 {
+			return nil, fmt.Errorf("column %s is mapped to several fields of %s", f.column, t)
+		}
+		fields = append(fields, f)
+	}
+
+	structFieldsCache.Store(t, fields)
+	return fields, nil
+}
+
+func collectStructFields(t reflect.Type, index []int, fields *[]structField, depths map[string][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup(structTag)
+		if tag == "-" {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		if f.Anonymous && !tagged {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !isScalarStruct(f.Type) {
+				collectStructFields(ft, fieldIndex, fields, depths)
+				continue
+			}
+		}
+		if !tagged || len(f.PkgPath) > 0 {
+			continue
+		}
+
+		opts := strings.Split(tag, ",")
+		field := structField{column: opts[0], index: fieldIndex}
+		if len(field.column) == 0 {
+			field.column = strings.ToLower(f.Name)
+		}
+		for _, opt := range opts[1:] {
+			switch opt {
+			case "omitempty":
+				field.omitEmpty = true
+			case "readonly":
+				field.readOnly = true
+			case "pk":
+				field.pk = true
+			}
+		}
+		*fields = append(*fields, field)
+		depths[field.column] = append(depths[field.column], len(fieldIndex))
+	}
+}
+
+// isScalarStruct reports whether values of the struct type t are column
+// values rather than structs to map, e.g. time.Time or sql.NullString.
+func isScalarStruct(t reflect.Type) bool {
+	return t == timeType || t.Implements(valuerType) || reflect.PtrTo(t).Implements(valuerType)
+}
+
+// fieldValue returns the field of the struct v at index, and false when it
+// is in an embedded struct that is a nil pointer.
+func fieldValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue reports whether the field v is empty for omitempty.
+func isEmptyValue(v reflect.Value) bool {
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return true
+		}
+		value, err := valuer.Value()
+		return err == nil && value == nil
+	}
+	return v.IsZero()
+}
+
+// structValues returns the struct values of v, a struct, a pointer to one, or
+// a slice of either.
+func structValues(v interface{}) (reflect.Type, []reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil, nil, errors.New("expected a struct or a slice of structs, not nil")
+	}
+
+	var values []reflect.Value
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		if rv.Len() == 0 {
+			return nil, nil, errors.New("expected at least one struct, got an empty slice")
+		}
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, rv.Index(i))
+		}
+	} else {
+		values = append(values, rv)
+	}
+
+	var t reflect.Type
+	for i, value := range values {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return nil, nil, fmt.Errorf("expected a struct, not nil %s", value.Type())
+			}
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.Struct {
+			return nil, nil, fmt.Errorf("expected a struct, not %s", value.Type())
+		}
+		if t == nil {
+			t = value.Type()
+		} else if value.Type() != t {
+			return nil, nil, fmt.Errorf("expected structs of a single type, not %s and %s", t, value.Type())
+		}
+		values[i] = value
+	}
+	return t, values, nil
+}
+
+// structColumns returns the columns written from the structs v and the values
+// of each of them. readOnly columns are never written, nor are pk columns
+// unless withPK is set, nor omitempty columns empty in every struct. An
+// omitempty column empty in some of the structs only is an error, as its zero
+// value would be written for them.
+func structColumns(v interface{}, withPK bool) (columns []string, rows [][]interface{}, err error) {
+	t, values, err := structValues(v)
+	if err != nil {
+		return
+	}
+	fields, err := structFields(t)
+	if err != nil {
+		return
+	}
+
+	rows = make([][]interface{}, len(values))
+	for _, f := range fields {
+		if f.readOnly || (f.pk && !withPK) {
+			continue
+		}
+
+		column := make([]interface{}, len(values))
+		emptyRow, setRow := -1, -1
+		for i, value := range values {
+			fv, ok := fieldValue(value, f.index)
+			if ok {
+				column[i] = fv.Interface()
+			}
+			if !f.omitEmpty {
+				continue
+			}
+			if !ok || isEmptyValue(fv) {
+				if emptyRow < 0 {
+					emptyRow = i
+				}
+			} else if setRow < 0 {
+				setRow = i
+			}
+		}
+		if emptyRow >= 0 {
+			if setRow < 0 {
+				continue
+			}
+			err = fmt.Errorf("omitempty column %s is empty in struct %d but not in struct %d", f.column, emptyRow, setRow)
+			return
+		}
+
+		columns = append(columns, f.column)
+		for i := range rows {
+			rows[i] = append(rows[i], column[i])
+		}
+	}
+
+	if len(columns) == 0 {
+		err = fmt.Errorf("%s has no columns to write", t)
+	}
+	return
+}
@@ -0,0 +1,116 @@
+package squirrel
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type structTestBase struct {
+	ID        int64     `db:"id,pk,omitempty"`
+	CreatedAt time.Time `db:"created_at,readonly"`
+}
+
+type structTestAudit struct {
+	UpdatedBy string `db:"updated_by,omitempty"`
+}
+
+type structTestUser struct {
+	structTestBase
+	*structTestAudit
+	Name     string         `db:"name"`
+	Email    sql.NullString `db:"email,omitempty"`
+	Age      int            `db:",omitempty"`
+	Password string         `db:"-"`
+	Ignored  string
+	hidden   string `db:"hidden"`
+}
+
+func TestInsertBuilderSetStruct(t *testing.T) {
+	user := structTestUser{
+		Name:            "moe",
+		Email:           sql.NullString{String: "moe@example.com", Valid: true},
+		structTestAudit: &structTestAudit{UpdatedBy: "larry"},
+		hidden:          "x",
+	}
+
+	sql, args, err := Insert("users").SetStruct(&user).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (updated_by,name,email) VALUES (?,?,?)", sql)
+	assert.Equal(t, []interface{}{"larry", "moe", user.Email}, args)
+}
+
+func TestInsertBuilderSetStructSlice(t *testing.T) {
+	users := []structTestUser{
+		{structTestBase: structTestBase{ID: 1}, Name: "moe", Age: 40},
+		{structTestBase: structTestBase{ID: 2}, Name: "larry", Age: 42},
+	}
+
+	sql, args, err := Insert("users").SetStruct(users).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name,age) VALUES (?,?,?),(?,?,?)", sql)
+	assert.Equal(t, []interface{}{int64(1), "moe", 40, int64(2), "larry", 42}, args)
+
+	// The zero value of an omitempty column empty in some of the structs only
+	// would be inserted for them.
+	users = []structTestUser{
+		{structTestBase: structTestBase{ID: 1}, Name: "moe"},
+		{Name: "larry", Age: 42},
+	}
+	_, _, err = Insert("users").SetStruct(users).ToSql()
+	assert.EqualError(t, err, "omitempty column id is empty in struct 1 but not in struct 0")
+}
+
+func TestInsertBuilderSetStructErr(t *testing.T) {
+	for _, v := range []interface{}{
+		nil,
+		42,
+		[]structTestUser{},
+		(*structTestUser)(nil),
+		[]interface{}{structTestUser{}, structTestAudit{}},
+		struct {
+			A int `db:"a"`
+			B int `db:"a"`
+		}{},
+		struct{ A int }{},
+	} {
+		_, _, err := Insert("users").SetStruct(v).ToSql()
+		assert.Error(t, err, "%#v", v)
+	}
+
+	_, _, err := Insert("users").SetStruct(42).SetStruct(structTestUser{Name: "moe"}).ToSql()
+	assert.NoError(t, err)
+}
+
+func TestUpdateBuilderSetStruct(t *testing.T) {
+	user := structTestUser{
+		structTestBase: structTestBase{ID: 7, CreatedAt: time.Now()},
+		Name:           "moe",
+	}
+
+	sql, args, err := Update("users").SetStruct(user).Where(Eq{"id": user.ID}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ? WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{"moe", int64(7)}, args)
+
+	_, _, err = Update("users").SetStruct([]structTestUser{user}).ToSql()
+	assert.Error(t, err)
+}
+
+func TestStructFieldsShadowing(t *testing.T) {
+	type inner struct {
+		Name string `db:"name"`
+		Kind string `db:"kind"`
+	}
+	type outer struct {
+		inner
+		Name string `db:"name"`
+	}
+
+	sql, args, err := Insert("t").SetStruct(outer{inner: inner{Name: "a", Kind: "b"}, Name: "c"}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (kind,name) VALUES (?,?)", sql)
+	assert.Equal(t, []interface{}{"b", "c"}, args)
+}
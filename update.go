@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"database/sql"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -12,15 +13,22 @@ import (
 
 type updateData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
 	RunWith           BaseRunner
 	Prefixes          exprs
 	Table             string
+	From              Sqlizer
+	Joins             []Sqlizer
 	SetClauses        []setClause
+	StructErr         error
 	WhereParts        []Sqlizer
 	OrderBys          []string
 	Limit             string
 	Offset            string
+	Returning         []string
 	Suffixes          exprs
+	AllRows           bool
+	statementOptions
 }
 
 type setClause struct {
@@ -29,31 +37,22 @@ type setClause struct {
 }
 
 func (d *updateData) Exec() (sql.Result, error) {
-	if d.RunWith == nil {
-		return nil, RunnerNotSet
-	}
-	return ExecWith(d.RunWith, d)
+	return execWithRunner(d.RunWith, d)
 }
 
 func (d *updateData) Query() (*sql.Rows, error) {
-	if d.RunWith == nil {
-		return nil, RunnerNotSet
-	}
-	return QueryWith(d.RunWith, d)
+	return queryWithRunner(d.RunWith, d)
 }
 
 func (d *updateData) QueryRow() RowScanner {
-	if d.RunWith == nil {
-		return &Row{err: RunnerNotSet}
-	}
-	queryRower, ok := d.RunWith.(QueryRower)
-	if !ok {
-		return &Row{err: RunnerNotQueryRunner}
-	}
-	return QueryRowWith(queryRower, d)
+	return queryRowWithRunner(d.RunWith, d)
 }
 
 func (d *updateData) ToSql() (sqlStr string, args []interface{}, err error) {
+	if d.StructErr != nil {
+		err = d.StructErr
+		return
+	}
 	if len(d.Table) == 0 {
 		err = fmt.Errorf("update statements must specify a table")
 		return
@@ -63,24 +62,58 @@ func (d *updateData) ToSql() (sqlStr string, args []interface{}, err error) {
 		return
 	}
 
+	pagination := dialectOr(d.Dialect).pagination()
+	top, err := mutationTop(dialectOr(d.Dialect), d.Limit, d.Offset)
+	if err != nil {
+		return
+	}
+	returning, output, err := returningClauses(d.Dialect, d.Returning, outputInserted)
+	if err != nil {
+		return
+	}
+	if err = d.checkTables(); err != nil {
+		return
+	}
+	if d.StrictIdents {
+		if err = d.checkIdents(); err != nil {
+			return
+		}
+	}
+
 	sql := &bytes.Buffer{}
 
 	if len(d.Prefixes) > 0 {
-		args, _ = d.Prefixes.AppendToSql(sql, " ", args)
+		args, err = d.Prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
 	sql.WriteString("UPDATE ")
+	sql.WriteString(top)
 	sql.WriteString(d.Table)
 
+	if len(d.Joins) > 0 && d.From == nil {
+		sql.WriteString(" ")
+		args, err = appendToSqlDialect(d.Dialect, d.Joins, sql, " ", args)
+		if err != nil {
+			return
+		}
+	}
+
 	sql.WriteString(" SET ")
 	setSqls := make([]string, len(d.SetClauses))
 	for i, setClause := range d.SetClauses {
 		var valSql string
 		e, isExpr := setClause.value.(expr)
 		if isExpr {
-			valSql = e.sql
-			args = append(args, e.args...)
+			exprSql, exprArgs, err := e.ToSql()
+			if err != nil {
+				return "", nil, err
+			}
+			valSql = exprSql
+			args = append(args, exprArgs...)
 		} else if c, isCase := setClause.value.(CaseBuilder); isCase {
 			caseSql, caseArgs, err := c.ToSql()
 			if err != nil {
@@ -96,38 +129,117 @@ func (d *updateData) ToSql() (sqlStr string, args []interface{}, err error) {
 	}
 	sql.WriteString(strings.Join(setSqls, ", "))
 
-	if len(d.WhereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSql(d.WhereParts, sql, " AND ", args)
+	if len(output) > 0 {
+		sql.WriteString(" ")
+		sql.WriteString(output)
+	}
+
+	if d.From != nil {
+		sql.WriteString(" FROM ")
+		args, err = appendToSqlDialect(d.Dialect, []Sqlizer{d.From}, sql, "", args)
 		if err != nil {
 			return
 		}
+		if len(d.Joins) > 0 {
+			sql.WriteString(" ")
+			args, err = appendToSqlDialect(d.Dialect, d.Joins, sql, " ", args)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	where := &bytes.Buffer{}
+	args, err = appendToSqlDialect(d.Dialect, d.WhereParts, where, " AND ", args)
+	if err != nil {
+		return
+	}
+	if !d.AllRows && isAlwaysTrue(d.WhereParts) {
+		err = fmt.Errorf("update %s: %w; call AllRows to allow it", d.Table, UnfilteredMutation)
+		return
+	}
+	if where.Len() > 0 {
+		sql.WriteString(" WHERE ")
+		where.WriteTo(sql)
 	}
 
+	sql.WriteString(returning)
+
 	if len(d.OrderBys) > 0 {
 		sql.WriteString(" ORDER BY ")
 		sql.WriteString(strings.Join(d.OrderBys, ", "))
 	}
 
-	if len(d.Limit) > 0 {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(d.Limit)
-	}
-
-	if len(d.Offset) > 0 {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(d.Offset)
+	if pagination == paginationLimitOffset {
+		if err = appendPaginationToSql(sql, pagination, d.Limit, d.Offset); err != nil {
+			return
+		}
 	}
 
 	if len(d.Suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = d.Suffixes.AppendToSql(sql, " ", args)
+		args, err = d.Suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if d.CheckParams {
+		if err = d.checkParams(sql.String(), args); err != nil {
+			return
+		}
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr, args, err = finalizePlaceholders(d.PlaceholderFormat, sql.String(), args)
 	return
 }
 
+// checkTables checks that the Dialect supports the way the query references
+// other tables, with From or with joins.
+func (d *updateData) checkTables() error {
+	if d.From == nil && len(d.Joins) == 0 {
+		return nil
+	}
+	switch dialectOr(d.Dialect).multiTable() {
+	case multiTableNone:
+		return fmt.Errorf("%s dialect does not support updates referencing other tables", d.Dialect.Name())
+	case multiTableFrom:
+		if d.From == nil {
+			return fmt.Errorf("%s dialect does not support UPDATE ... JOIN; join the tables of From instead", d.Dialect.Name())
+		}
+	case multiTableJoin:
+		if d.From != nil {
+			return fmt.Errorf("%s dialect does not support UPDATE ... FROM; use Join instead", d.Dialect.Name())
+		}
+	}
+	return nil
+}
+
+// checkIdents checks the identifiers of the query for strict mode.
+func (d *updateData) checkIdents() error {
+	c := newIdentChecker(d.Dialect)
+	if err := c.check(d.Table, identTable); err != nil {
+		return err
+	}
+	if d.From != nil {
+		if err := c.checkParts([]Sqlizer{d.From}, identTable); err != nil {
+			return err
+		}
+	}
+	for _, setClause := range d.SetClauses {
+		if err := c.check(setClause.column, identName); err != nil {
+			return err
+		}
+	}
+	if err := c.checkExprs(d.Joins); err != nil {
+		return err
+	}
+	if err := c.checkExprs(d.WhereParts); err != nil {
+		return err
+	}
+	return c.checkAll(d.OrderBys, identOrder)
+}
+
 // Builder
 
 // UpdateBuilder builds SQL UPDATE statements.
@@ -145,6 +257,16 @@ func (b UpdateBuilder) PlaceholderFormat(f PlaceholderFormat) UpdateBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(UpdateBuilder)
 }
 
+func (b UpdateBuilder) debugPlaceholder() string {
+	data := builder.GetStruct(b).(updateData)
+	return debugPlaceholder(data.PlaceholderFormat)
+}
+
+// Dialect sets the Dialect of the query, along with its PlaceholderFormat.
+func (b UpdateBuilder) Dialect(d Dialect) UpdateBuilder {
+	return setDialect(b, d).(UpdateBuilder)
+}
+
 // Runner methods
 
 // RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
@@ -172,6 +294,32 @@ func (b UpdateBuilder) Scan(dest ...interface{}) error {
 	return b.QueryRow().Scan(dest...)
 }
 
+// ScanStruct builds and Querys the query with the Runner set by RunWith and
+// scans the first returned row into dest.
+//
+// See SelectBuilder.ScanStruct for the values dest can point to.
+func (b UpdateBuilder) ScanStruct(dest interface{}) error {
+	data := builder.GetStruct(b).(updateData)
+	rows, err := data.Query()
+	if err != nil {
+		return err
+	}
+	return scanOne(rows, dest, false)
+}
+
+// ScanAll builds and Querys the query with the Runner set by RunWith and
+// scans all the returned rows into dest, a pointer to a slice.
+//
+// See SelectBuilder.ScanAll for the slices dest can point to.
+func (b UpdateBuilder) ScanAll(dest interface{}) error {
+	data := builder.GetStruct(b).(updateData)
+	rows, err := data.Query()
+	if err != nil {
+		return err
+	}
+	return scanAll(rows, dest, false)
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -190,6 +338,52 @@ func (b UpdateBuilder) Table(table string) UpdateBuilder {
 	return builder.Set(b, "Table", table).(UpdateBuilder)
 }
 
+// From adds a FROM clause to the query, listing the other tables the SET and
+// WHERE clauses can reference, as in PostgreSQL's
+//   UPDATE orders SET total = t.total FROM totals t WHERE orders.id = t.id
+// The joins of the query are rendered after it. MySQL has no FROM clause for
+// updates; use Join instead.
+func (b UpdateBuilder) From(from string) UpdateBuilder {
+	return builder.Set(b, "From", newPart(from)).(UpdateBuilder)
+}
+
+// FromSelect sets a subquery into the FROM clause of the query.
+func (b UpdateBuilder) FromSelect(from SelectBuilder, alias string) UpdateBuilder {
+	return builder.Set(b, "From", derivedTable{query: from, alias: alias}).(UpdateBuilder)
+}
+
+// JoinClause adds a join clause to the query.
+//
+// Without From, joins are rendered after the updated table, as in MySQL's
+//   UPDATE orders JOIN totals t ON orders.id = t.id SET total = t.total
+// With From, they join the tables of the FROM clause.
+func (b UpdateBuilder) JoinClause(pred interface{}, args ...interface{}) UpdateBuilder {
+	return builder.Append(b, "Joins", newPart(pred, args...)).(UpdateBuilder)
+}
+
+// Join adds a JOIN clause to the query.
+func (b UpdateBuilder) Join(join string, rest ...interface{}) UpdateBuilder {
+	return b.JoinClause("JOIN "+join, rest...)
+}
+
+// LeftJoin adds a LEFT JOIN clause to the query.
+func (b UpdateBuilder) LeftJoin(join string, rest ...interface{}) UpdateBuilder {
+	return b.JoinClause("LEFT JOIN "+join, rest...)
+}
+
+// RightJoin adds a RIGHT JOIN clause to the query.
+func (b UpdateBuilder) RightJoin(join string, rest ...interface{}) UpdateBuilder {
+	return b.JoinClause("RIGHT JOIN "+join, rest...)
+}
+
+// JoinSelect adds a JOIN clause joining a subquery aliased as alias on the
+// condition on, e.g.
+//   JoinSelect(Select("order_id", "SUM(amount) AS total").From("items").GroupBy("order_id"),
+//       "t", "orders.id = t.order_id")
+func (b UpdateBuilder) JoinSelect(join SelectBuilder, alias string, on string, args ...interface{}) UpdateBuilder {
+	return b.JoinClause(ConcatExpr("JOIN ", derivedTable{query: join, alias: alias}, " ON ", Expr(on, args...)))
+}
+
 // Set adds SET clauses to the query.
 func (b UpdateBuilder) Set(column string, value interface{}) UpdateBuilder {
 	return builder.Append(b, "SetClauses", setClause{column: column, value: value}).(UpdateBuilder)
@@ -211,6 +405,23 @@ func (b UpdateBuilder) SetMap(clauses map[string]interface{}) UpdateBuilder {
 	return b
 }
 
+// SetStruct is a convenience method which calls .Set for each column mapped to
+// a field of the struct v by its db tag (see structTag). Primary key and
+// read-only columns are not set, nor are empty omitempty columns.
+func (b UpdateBuilder) SetStruct(v interface{}) UpdateBuilder {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		return builder.Set(b, "StructErr", fmt.Errorf("SetStruct expects a single struct, not %T", v)).(UpdateBuilder)
+	}
+	columns, rows, err := structColumns(v, false)
+	if err != nil {
+		return builder.Set(b, "StructErr", err).(UpdateBuilder)
+	}
+	for i, column := range columns {
+		b = b.Set(column, rows[0][i])
+	}
+	return b
+}
+
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
@@ -233,6 +444,24 @@ func (b UpdateBuilder) Offset(offset uint64) UpdateBuilder {
 	return builder.Set(b, "Offset", fmt.Sprintf("%d", offset)).(UpdateBuilder)
 }
 
+// AllRows allows the query to update every row of the table, which ToSql
+// otherwise refuses to do when the query has no WHERE clause or only always
+// true ones, such as an empty Eq.
+func (b UpdateBuilder) AllRows() UpdateBuilder {
+	return builder.Set(b, "AllRows", true).(UpdateBuilder)
+}
+
+// Returning adds a clause returning columns of the updated rows, e.g. to
+// fetch generated IDs, rendered as a RETURNING clause or, on SQL Server, as an
+// OUTPUT clause. It returns an error on dialects supporting neither, such as
+// MySQL.
+//
+// Run the statement with Query, QueryRow, ScanStruct or ScanAll to read the
+// returned rows.
+func (b UpdateBuilder) Returning(columns ...string) UpdateBuilder {
+	return builder.Extend(b, "Returning", columns).(UpdateBuilder)
+}
+
 // Suffix adds an expression to the end of the query
 func (b UpdateBuilder) Suffix(sql string, args ...interface{}) UpdateBuilder {
 	return builder.Append(b, "Suffixes", Expr(sql, args...)).(UpdateBuilder)
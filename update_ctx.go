@@ -10,39 +10,15 @@ import (
 )
 
 func (d *updateData) ExecContext(ctx context.Context) (sql.Result, error) {
-	if d.RunWith == nil {
-		return nil, RunnerNotSet
-	}
-	ctxRunner, ok := d.RunWith.(ExecerContext)
-	if !ok {
-		return nil, NoContextSupport
-	}
-	return ExecContextWith(ctx, ctxRunner, d)
+	return execContextWithRunner(ctx, d.RunWith, d)
 }
 
 func (d *updateData) QueryContext(ctx context.Context) (*sql.Rows, error) {
-	if d.RunWith == nil {
-		return nil, RunnerNotSet
-	}
-	ctxRunner, ok := d.RunWith.(QueryerContext)
-	if !ok {
-		return nil, NoContextSupport
-	}
-	return QueryContextWith(ctx, ctxRunner, d)
+	return queryContextWithRunner(ctx, d.RunWith, d)
 }
 
 func (d *updateData) QueryRowContext(ctx context.Context) RowScanner {
-	if d.RunWith == nil {
-		return &Row{err: RunnerNotSet}
-	}
-	queryRower, ok := d.RunWith.(QueryRowerContext)
-	if !ok {
-		if _, ok := d.RunWith.(QueryerContext); !ok {
-			return &Row{err: RunnerNotQueryRunner}
-		}
-		return &Row{err: NoContextSupport}
-	}
-	return QueryRowContextWith(ctx, queryRower, d)
+	return queryRowContextWithRunner(ctx, d.RunWith, d)
 }
 
 // ExecContext builds and ExecContexts the query with the Runner set by RunWith.
@@ -67,3 +43,23 @@ func (b UpdateBuilder) QueryRowContext(ctx context.Context) RowScanner {
 func (b UpdateBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
 	return b.QueryRowContext(ctx).Scan(dest...)
 }
+
+// ScanStructContext is like ScanStruct, using QueryContext.
+func (b UpdateBuilder) ScanStructContext(ctx context.Context, dest interface{}) error {
+	data := builder.GetStruct(b).(updateData)
+	rows, err := data.QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	return scanOne(rows, dest, false)
+}
+
+// ScanAllContext is like ScanAll, using QueryContext.
+func (b UpdateBuilder) ScanAllContext(ctx context.Context, dest interface{}) error {
+	data := builder.GetStruct(b).(updateData)
+	rows, err := data.QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	return scanAll(rows, dest, false)
+}
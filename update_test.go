@@ -1,6 +1,7 @@
 package squirrel
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,7 +47,7 @@ func TestUpdateBuilderToSqlErr(t *testing.T) {
 }
 
 func TestUpdateBuilderPlaceholders(t *testing.T) {
-	b := Update("test").SetMap(Eq{"x": 1, "y": 2})
+	b := Update("test").SetMap(Eq{"x": 1, "y": 2}).AllRows()
 
 	sql, _, _ := b.PlaceholderFormat(Question).ToSql()
 	assert.Equal(t, "UPDATE test SET x = ?, y = ?", sql)
@@ -57,7 +58,7 @@ func TestUpdateBuilderPlaceholders(t *testing.T) {
 
 func TestUpdateBuilderRunners(t *testing.T) {
 	db := &DBStub{}
-	b := Update("test").Set("x", 1).RunWith(db)
+	b := Update("test").Set("x", 1).AllRows().RunWith(db)
 
 	expectedSql := "UPDATE test SET x = ?"
 
@@ -71,3 +72,113 @@ func TestUpdateBuilderNoRunner(t *testing.T) {
 	_, err := b.Exec()
 	assert.Equal(t, RunnerNotSet, err)
 }
+
+func TestUpdateBuilderFrom(t *testing.T) {
+	totals := Select("order_id", "SUM(amount) AS total").From("items").
+		Where("status = ?", "paid").GroupBy("order_id")
+
+	sql, args, err := Update("orders").
+		Set("total", Expr("t.total")).
+		Set("synced", true).
+		FromSelect(totals, "t").
+		Where("orders.id = t.order_id").
+		Where("orders.total <> ?", 0).
+		Dialect(PostgreSQL).
+		ToSql()
+	assert.NoError(t, err)
+	expectedSql := "UPDATE orders SET total = t.total, synced = $1 " +
+		"FROM (SELECT order_id, SUM(amount) AS total FROM items WHERE status = $2 GROUP BY order_id) AS t " +
+		"WHERE orders.id = t.order_id AND orders.total <> $3"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{true, "paid", 0}, args)
+
+	sql, _, err = Update("orders").
+		Set("total", Expr("i.amount")).
+		From("orders o").
+		Join("items i ON i.order_id = o.id").
+		Where("orders.id = o.id").
+		Dialect(SQLServer).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE orders SET total = i.amount FROM orders o JOIN items i ON i.order_id = o.id WHERE orders.id = o.id", sql)
+}
+
+func TestUpdateBuilderJoin(t *testing.T) {
+	totals := Select("order_id", "SUM(amount) AS total").From("items").
+		Where("status = ?", "paid").GroupBy("order_id")
+
+	sql, args, err := Update("orders o").
+		JoinSelect(totals, "t", "o.id = t.order_id AND t.total > ?", 10).
+		LeftJoin("customers c ON c.id = o.customer_id").
+		Set("o.total", Expr("t.total")).
+		Set("o.synced", true).
+		Where("c.active = ?", true).
+		Dialect(MySQL).
+		ToSql()
+	assert.NoError(t, err)
+	expectedSql := "UPDATE orders o " +
+		"JOIN (SELECT order_id, SUM(amount) AS total FROM items WHERE status = ? GROUP BY order_id) AS t " +
+		"ON o.id = t.order_id AND t.total > ? " +
+		"LEFT JOIN customers c ON c.id = o.customer_id " +
+		"SET o.total = t.total, o.synced = ? WHERE c.active = ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"paid", 10, true, true}, args)
+
+	sql, args, err = Update("a").Join("b USING (id)").Set("x", Expr("b.x")).Where("a.y = ?", 1).
+		PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE a JOIN b USING (id) SET x = b.x WHERE a.y = $1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestUpdateBuilderTablesDialectErrors(t *testing.T) {
+	_, _, err := Update("a").Join("b USING (id)").Set("x", 1).Dialect(PostgreSQL).ToSql()
+	assert.EqualError(t, err, "postgres dialect does not support UPDATE ... JOIN; join the tables of From instead")
+
+	_, _, err = Update("a").From("b").Set("x", 1).Dialect(MySQL).ToSql()
+	assert.EqualError(t, err, "mysql dialect does not support UPDATE ... FROM; use Join instead")
+
+	_, _, err = Update("a").From("b").Set("x", 1).Dialect(Oracle).ToSql()
+	assert.EqualError(t, err, "oracle dialect does not support updates referencing other tables")
+}
+
+func TestUpdateBuilderUnfiltered(t *testing.T) {
+	b := Update("t").Set("a", 1)
+
+	_, _, err := b.ToSql()
+	assert.EqualError(t, err, "update t: statement would affect every row of the table; call AllRows to allow it")
+	assert.True(t, errors.Is(err, UnfilteredMutation))
+
+	for _, where := range []interface{}{"", Eq{}, And{}, And{Eq{}, And{}}, "(1=1) AND (1=1)"} {
+		_, _, err = b.Where(where).ToSql()
+		assert.True(t, errors.Is(err, UnfilteredMutation), "%#v", where)
+	}
+	_, _, err = b.Where(Eq{}).Dialect(PostgreSQL).ToSql()
+	assert.True(t, errors.Is(err, UnfilteredMutation))
+
+	for _, where := range []interface{}{Or{}, Or{Like{}, Eq{"a": 1}}, NotEq{"a": []int{1}}, "(1=1) OR a = 1"} {
+		_, _, err = b.Where(where).ToSql()
+		assert.NoError(t, err, "%#v", where)
+	}
+	for _, where := range []interface{}{Like{}, Or{Eq{}, Eq{"a": 1}}, NotEq{"a": []int{}}} {
+		_, _, err = b.Where(where).ToSql()
+		assert.True(t, errors.Is(err, UnfilteredMutation), "%#v", where)
+	}
+
+	// The SQLite true literal, 1, is part of real predicates.
+	sql, _, err := b.Where("11").Dialect(SQLite).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ? WHERE 11", sql)
+
+	sql, _, err = b.Where(Eq{}).Where("active = TRUE").Dialect(PostgreSQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = $1 WHERE TRUE AND active = TRUE", sql)
+
+	sql, _, err = b.AllRows().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ?", sql)
+
+	_, err = b.RunWith(&DBStub{}).Exec()
+	assert.True(t, errors.Is(err, UnfilteredMutation))
+	assert.True(t, IsConfigError(err))
+}
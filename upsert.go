@@ -0,0 +1,171 @@
+package squirrel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// UpsertFormat is the interface that wraps the rendering of the conflict
+// clause of an InsertBuilder, as configured with OnConflict, DoNothing,
+// DoUpdateSet and DoUpdateWhere.
+type UpsertFormat interface {
+	appendUpsertToSql(w io.Writer, d *insertData, args []interface{}) ([]interface{}, error)
+}
+
+var (
+	// OnConflictUpsert is an UpsertFormat instance that renders PostgreSQL and
+	// SQLite ON CONFLICT clauses (e.g. ON CONFLICT (id) DO UPDATE SET
+	// name = EXCLUDED.name). It is the default.
+	OnConflictUpsert = onConflictFormat{}
+
+	// OnDuplicateKeyUpsert is an UpsertFormat instance that renders MySQL
+	// ON DUPLICATE KEY UPDATE clauses (e.g. ON DUPLICATE KEY UPDATE
+	// name = VALUES(name)).
+	OnDuplicateKeyUpsert = onDuplicateKeyFormat{}
+)
+
+// excludedExpr references the value proposed for insertion into a column.
+type excludedExpr string
+
+// Excluded references the value that was proposed for insertion into column,
+// for use as a DoUpdateSet value:
+//   DoUpdateSet("name", Excluded("name"))
+// It is rendered as EXCLUDED.name by OnConflictUpsert and as VALUES(name) by
+// OnDuplicateKeyUpsert.
+func Excluded(column string) Sqlizer {
+	return excludedExpr(column)
+}
+
+func (e excludedExpr) ToSql() (sql string, args []interface{}, err error) {
+	return "EXCLUDED." + string(e), nil, nil
+}
+
+func (d *insertData) hasUpsert() bool {
+	return len(d.ConflictColumns) > 0 || len(d.ConflictConstraint) > 0 ||
+		d.ConflictDoNothing || len(d.ConflictSetClauses) > 0 || len(d.ConflictWhereParts) > 0
+}
+
+// appendUpsertSetToSql writes the DoUpdateSet clauses to w, rendering Excluded
+// values with excluded.
+func appendUpsertSetToSql(w io.Writer, clauses []setClause, excluded func(string) string, args []interface{}) ([]interface{}, error) {
+	setSqls := make([]string, len(clauses))
+	for i, c := range clauses {
+		var valSql string
+		switch v := c.value.(type) {
+		case excludedExpr:
+			valSql = excluded(string(v))
+		case Sqlizer:
+			vSql, vArgs, err := nestedToSql(v)
+			if err != nil {
+				return nil, err
+			}
+			valSql = vSql
+			args = append(args, vArgs...)
+		default:
+			valSql = "?"
+			args = append(args, v)
+		}
+		setSqls[i] = fmt.Sprintf("%s = %s", c.column, valSql)
+	}
+	_, err := io.WriteString(w, strings.Join(setSqls, ", "))
+	return args, err
+}
+
+type onConflictFormat struct{}
+
+func (onConflictFormat) appendUpsertToSql(w io.Writer, d *insertData, args []interface{}) ([]interface{}, error) {
+	if d.ConflictDoNothing && len(d.ConflictSetClauses) > 0 {
+		return nil, errors.New("upsert cannot both DoNothing and DoUpdateSet")
+	}
+	if !d.ConflictDoNothing && len(d.ConflictSetClauses) == 0 {
+		return nil, errors.New("upsert must either DoNothing or DoUpdateSet")
+	}
+	if len(d.ConflictColumns) > 0 && len(d.ConflictConstraint) > 0 {
+		return nil, errors.New("upsert conflict target must be either columns or a constraint, not both")
+	}
+
+	sql := &bytes.Buffer{}
+	sql.WriteString(" ON CONFLICT")
+
+	if len(d.ConflictColumns) > 0 {
+		sql.WriteString(" (")
+		sql.WriteString(strings.Join(d.ConflictColumns, ","))
+		sql.WriteString(")")
+	} else if len(d.ConflictConstraint) > 0 {
+		sql.WriteString(" ON CONSTRAINT ")
+		sql.WriteString(d.ConflictConstraint)
+	} else if !d.ConflictDoNothing {
+		return nil, errors.New("upsert with DoUpdateSet must specify a conflict target")
+	}
+
+	if d.ConflictDoNothing {
+		if len(d.ConflictWhereParts) > 0 {
+			return nil, errors.New("upsert with DoNothing cannot have DoUpdateWhere clauses")
+		}
+		sql.WriteString(" DO NOTHING")
+		_, err := io.WriteString(w, sql.String())
+		return args, err
+	}
+
+	sql.WriteString(" DO UPDATE SET ")
+	args, err := appendUpsertSetToSql(sql, d.ConflictSetClauses, func(column string) string {
+		return "EXCLUDED." + column
+	}, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.ConflictWhereParts) > 0 {
+		sql.WriteString(" WHERE ")
+		args, err = appendToSql(d.ConflictWhereParts, sql, " AND ", args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = io.WriteString(w, sql.String())
+	return args, err
+}
+
+type onDuplicateKeyFormat struct{}
+
+func (onDuplicateKeyFormat) appendUpsertToSql(w io.Writer, d *insertData, args []interface{}) ([]interface{}, error) {
+	if d.ConflictDoNothing && len(d.ConflictSetClauses) > 0 {
+		return nil, errors.New("upsert cannot both DoNothing and DoUpdateSet")
+	}
+	if !d.ConflictDoNothing && len(d.ConflictSetClauses) == 0 {
+		return nil, errors.New("upsert must either DoNothing or DoUpdateSet")
+	}
+	if len(d.ConflictWhereParts) > 0 {
+		return nil, errors.New("ON DUPLICATE KEY UPDATE does not support DoUpdateWhere clauses")
+	}
+	if len(d.ConflictConstraint) > 0 {
+		// MySQL picks the conflicting unique key itself.
+		return nil, errors.New("ON DUPLICATE KEY UPDATE does not support OnConflictConstraint")
+	}
+
+	clauses := d.ConflictSetClauses
+	if d.ConflictDoNothing {
+		// MySQL has no DO NOTHING; assigning a column to itself is a no-op
+		// that, unlike INSERT IGNORE, does not hide unrelated errors.
+		var column string
+		if len(d.ConflictColumns) > 0 {
+			column = d.ConflictColumns[0]
+		} else if len(d.Columns) > 0 {
+			column = d.Columns[0]
+		} else {
+			return nil, errors.New("upsert with DoNothing needs a conflict column or insert column for ON DUPLICATE KEY UPDATE")
+		}
+		clauses = []setClause{{column: column, value: Expr(column)}}
+	}
+
+	if _, err := io.WriteString(w, " ON DUPLICATE KEY UPDATE "); err != nil {
+		return nil, err
+	}
+	return appendUpsertSetToSql(w, clauses, func(column string) string {
+		return fmt.Sprintf("VALUES(%s)", column)
+	}, args)
+}
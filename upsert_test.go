@@ -0,0 +1,147 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertOnConflictDoUpdate(t *testing.T) {
+	b := Insert("users").
+		Columns("id", "name", "visits").
+		Values(1, "moe", 1).
+		OnConflict("id").
+		DoUpdateSetExcluded("name").
+		DoUpdateSet("visits", Expr("users.visits + ?", 1)).
+		DoUpdateSet("note", "updated").
+		DoUpdateWhere("users.locked = ?", false).
+		Suffix("RETURNING id").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "INSERT INTO users (id,name,visits) VALUES ($1,$2,$3) " +
+		"ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, visits = users.visits + $4, note = $5 " +
+		"WHERE users.locked = $6 " +
+		"RETURNING id"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1, "moe", 1, 1, "updated", false}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestUpsertOnConflictDoNothing(t *testing.T) {
+	sql, _, err := Insert("users").Columns("id").Values(1).DoNothing().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id) VALUES (?) ON CONFLICT DO NOTHING", sql)
+
+	sql, _, err = Insert("users").Columns("id").Values(1).OnConflictConstraint("users_pkey").DoNothing().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id) VALUES (?) ON CONFLICT ON CONSTRAINT users_pkey DO NOTHING", sql)
+}
+
+func TestUpsertOnConflictSelect(t *testing.T) {
+	sb := Select("id", "name").From("staging").Where("batch = ?", 7)
+	b := Insert("users").Columns("id", "name").Select(sb).
+		OnConflict("id").
+		DoUpdateSetMap(map[string]interface{}{"name": Excluded("name"), "synced": true}).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"INSERT INTO users (id,name) SELECT id, name FROM staging WHERE batch = $1 "+
+			"ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, synced = $2",
+		sql)
+	assert.Equal(t, []interface{}{7, true}, args)
+}
+
+func TestUpsertOnDuplicateKey(t *testing.T) {
+	b := Insert("users").
+		Columns("id", "name", "visits").
+		Values(1, "moe", 1).
+		OnConflict("id").
+		DoUpdateSetExcluded("name").
+		DoUpdateSet("visits", Expr("visits + ?", 1)).
+		UpsertFormat(OnDuplicateKeyUpsert)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"INSERT INTO users (id,name,visits) VALUES (?,?,?) "+
+			"ON DUPLICATE KEY UPDATE name = VALUES(name), visits = visits + ?",
+		sql)
+	assert.Equal(t, []interface{}{1, "moe", 1, 1}, args)
+
+	sql, _, err = Insert("users").Columns("id", "name").Values(1, "moe").
+		DoNothing().UpsertFormat(OnDuplicateKeyUpsert).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name) VALUES (?,?) ON DUPLICATE KEY UPDATE id = id", sql)
+}
+
+func TestUpsertErrors(t *testing.T) {
+	base := Insert("users").Columns("id").Values(1)
+
+	_, _, err := base.OnConflict("id").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = base.OnConflict("id").DoNothing().DoUpdateSetExcluded("id").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = base.DoUpdateSetExcluded("id").ToSql()
+	assert.Error(t, err, "DO UPDATE requires a conflict target")
+
+	_, _, err = base.OnConflict("id").OnConflictConstraint("users_pkey").DoNothing().ToSql()
+	assert.Error(t, err)
+
+	_, _, err = base.OnConflict("id").DoNothing().DoUpdateWhere("x = 1").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = base.OnConflict("id").DoUpdateSetExcluded("id").DoUpdateWhere("x = 1").
+		UpsertFormat(OnDuplicateKeyUpsert).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Insert("users").Values(1).DoNothing().UpsertFormat(OnDuplicateKeyUpsert).ToSql()
+	assert.Error(t, err)
+}
+
+func TestUpsertOnDuplicateKeyUnsupported(t *testing.T) {
+	base := Insert("users").Columns("id", "name").Values(1, "a").Dialect(MySQL)
+
+	_, _, err := base.OnConflictConstraint("users_pkey").DoUpdateSetExcluded("name").ToSql()
+	assert.EqualError(t, err, "ON DUPLICATE KEY UPDATE does not support OnConflictConstraint")
+
+	_, _, err = base.OnConflictConstraint("users_pkey").DoNothing().ToSql()
+	assert.EqualError(t, err, "ON DUPLICATE KEY UPDATE does not support OnConflictConstraint")
+
+	_, _, err = base.OnConflict("id").DoUpdateSetExcluded("name").DoUpdateWhere("users.locked = ?", false).ToSql()
+	assert.EqualError(t, err, "ON DUPLICATE KEY UPDATE does not support DoUpdateWhere clauses")
+}
+
+func TestExcludedToSql(t *testing.T) {
+	sql, args, err := Excluded("name").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXCLUDED.name", sql)
+	assert.Empty(t, args)
+}
+
+func TestUpsertDoUpdateSetSubquery(t *testing.T) {
+	total := Select("SUM(amount)").From("orders").Where("user_id = ?", 1).
+		Prefix("(").Suffix(")").PlaceholderFormat(Dollar)
+	sql, args, err := Insert("users").
+		Columns("id", "total").
+		Values(1, 0).
+		OnConflict("id").
+		DoUpdateSet("total", total).
+		DoUpdateWhere("users.locked = ?", false).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "INSERT INTO users (id,total) VALUES ($1,$2) " +
+		"ON CONFLICT (id) DO UPDATE SET total = ( SELECT SUM(amount) FROM orders WHERE user_id = $3 ) " +
+		"WHERE users.locked = $4"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 0, 1, false}, args)
+}
@@ -1,7 +1,10 @@
 package squirrel
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 )
 
 type wherePart part
@@ -11,20 +14,130 @@ func newWherePart(pred interface{}, args ...interface{}) Sqlizer {
 }
 
 func (p wherePart) ToSql() (sql string, args []interface{}, err error) {
+	return p.toSqlDialect(nil)
+}
+
+func (p wherePart) toSqlDialect(d Dialect) (sql string, args []interface{}, err error) {
 	switch pred := p.pred.(type) {
 	case nil:
 		// no-op
 	case rawSqlizer:
 		return pred.toSqlRaw()
 	case Sqlizer:
-		return pred.ToSql()
+		return toSqlDialect(d, pred)
 	case map[string]interface{}:
-		return Eq(pred).ToSql()
+		return Eq(pred).toSqlDialect(d)
 	case string:
-		sql = pred
-		args = p.args
+		sql, args, err = bindNamedDialect(d, pred, p.args)
 	default:
 		err = fmt.Errorf("expected string-keyed map or string, not %T", pred)
 	}
 	return
 }
+
+// UnfilteredMutation is wrapped by the errors returned by the ToSql methods of
+// UpdateBuilder and DeleteBuilder for statements without a WHERE clause, or
+// with only always true ones, unless AllRows is set.
+var UnfilteredMutation = errors.New("statement would affect every row of the table")
+
+// isAlwaysTrue reports whether the WHERE clause made of parts is empty or only
+// made of always true predicates, e.g. an empty Eq or And, without rendering
+// them: a dialect's true literal could also be part of a real predicate.
+func isAlwaysTrue(parts []Sqlizer) bool {
+	for _, p := range parts {
+		if predTruth(p) == predFilters {
+			return false
+		}
+	}
+	return true
+}
+
+// predTruthValue is what predTruth knows of a predicate.
+type predTruthValue int
+
+const (
+	// the predicate may filter rows
+	predFilters predTruthValue = iota
+	// the predicate renders as nothing, e.g. an empty Like
+	predEmpty
+	// the predicate renders as a true literal, e.g. an empty Eq
+	predTrue
+)
+
+// predTruth reports whether pred, a predicate as given to Where, is known to
+// select every row.
+func predTruth(pred interface{}) predTruthValue {
+	switch p := pred.(type) {
+	case nil:
+		return predEmpty
+	case string:
+		// Strings are only known to select every row when they are made of
+		// the portable true literal, as rendered by an empty Eq.
+		for _, field := range strings.Fields(strings.Replace(p, sqlTrue, " ", -1)) {
+			if !strings.EqualFold(field, "AND") {
+				return predFilters
+			}
+		}
+		if strings.TrimSpace(p) == "" {
+			return predEmpty
+		}
+		return predTrue
+	case *wherePart:
+		return predTruth(p.pred)
+	case wherePart:
+		return predTruth(p.pred)
+	case *part:
+		return predTruth(p.pred)
+	case part:
+		return predTruth(p.pred)
+	case Eq:
+		if len(p) == 0 {
+			return predTrue
+		}
+		return predFilters
+	case map[string]interface{}:
+		return predTruth(Eq(p))
+	case NotEq:
+		// NOT IN an empty list is true as well.
+		for _, v := range p {
+			if !isListType(v) || reflect.ValueOf(v).Len() > 0 {
+				return predFilters
+			}
+		}
+		return predTrue
+	case And:
+		if len(p) == 0 {
+			return predTrue
+		}
+		truth := predEmpty
+		for _, c := range p {
+			switch predTruth(c) {
+			case predFilters:
+				return predFilters
+			case predTrue:
+				truth = predTrue
+			}
+		}
+		return truth
+	case Or:
+		if len(p) == 0 {
+			return predFilters
+		}
+		truth := predEmpty
+		for _, c := range p {
+			switch predTruth(c) {
+			case predTrue:
+				return predTrue
+			case predFilters:
+				truth = predFilters
+			}
+		}
+		return truth
+	}
+
+	// Comparison maps such as Like and Lt render as nothing when empty.
+	if rv := reflect.ValueOf(pred); rv.Kind() == reflect.Map && rv.Len() == 0 {
+		return predEmpty
+	}
+	return predFilters
+}
@@ -0,0 +1,236 @@
+package squirrel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+func init() {
+	builder.Register(WindowBuilder{}, windowData{})
+}
+
+// windowData holds all the data required to build a window function call or
+// a window specification
+type windowData struct {
+	Function     Sqlizer
+	Base         string
+	PartitionBys []Sqlizer
+	OrderByParts []Sqlizer
+	Frame        Sqlizer
+}
+
+// ToSql implements Sqlizer
+func (d *windowData) ToSql() (sqlStr string, args []interface{}, err error) {
+	return d.toSqlDialect(nil)
+}
+
+func (d *windowData) toSqlDialect(dialect Dialect) (sqlStr string, args []interface{}, err error) {
+	sql := &bytes.Buffer{}
+
+	if d.Function != nil {
+		var fnSql string
+		fnSql, args, err = toSqlDialect(dialect, d.Function)
+		if err != nil {
+			return
+		}
+		sql.WriteString(fnSql)
+		sql.WriteString(" OVER ")
+
+		// A window function over a named window needs no parentheses.
+		if len(d.Base) > 0 && len(d.PartitionBys) == 0 && len(d.OrderByParts) == 0 && d.Frame == nil {
+			sql.WriteString(d.Base)
+			return sql.String(), args, nil
+		}
+	}
+
+	var spec []string
+	if len(d.Base) > 0 {
+		spec = append(spec, d.Base)
+	}
+	if len(d.PartitionBys) > 0 {
+		partitionBy := &bytes.Buffer{}
+		args, err = appendToSqlDialect(dialect, d.PartitionBys, partitionBy, ", ", args)
+		if err != nil {
+			return
+		}
+		spec = append(spec, "PARTITION BY "+partitionBy.String())
+	}
+	if len(d.OrderByParts) > 0 {
+		orderBy := &bytes.Buffer{}
+		args, err = appendToSqlDialect(dialect, d.OrderByParts, orderBy, ", ", args)
+		if err != nil {
+			return
+		}
+		spec = append(spec, "ORDER BY "+orderBy.String())
+	}
+	if d.Frame != nil {
+		frame := &bytes.Buffer{}
+		args, err = appendToSqlDialect(dialect, []Sqlizer{d.Frame}, frame, "", args)
+		if err != nil {
+			return
+		}
+		spec = append(spec, frame.String())
+	}
+
+	sql.WriteString("(")
+	sql.WriteString(strings.Join(spec, " "))
+	sql.WriteString(")")
+
+	return sql.String(), args, nil
+}
+
+// WindowBuilder builds SQL window function calls, such as
+// ROW_NUMBER() OVER (PARTITION BY ... ORDER BY ...), and window specifications
+// for SelectBuilder.Window, which could be used as parts of queries.
+type WindowBuilder builder.Builder
+
+// ToSql builds the window into a SQL string and bound args.
+func (b WindowBuilder) ToSql() (string, []interface{}, error) {
+	data := builder.GetStruct(b).(windowData)
+	return data.ToSql()
+}
+
+func (b WindowBuilder) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	data := builder.GetStruct(b).(windowData)
+	return data.toSqlDialect(d)
+}
+
+// Over returns a WindowBuilder calling the window or aggregate function fn
+// over a window:
+//   Over("SUM(amount)").PartitionBy("account_id")
+//   Over("NTH_VALUE(price, ?)", 2).OrderBy("day")
+func Over(fn interface{}, args ...interface{}) WindowBuilder {
+	return builder.Set(WindowBuilder{}, "Function", newPart(fn, args...)).(WindowBuilder)
+}
+
+// WindowSpec returns a WindowBuilder for a window specification without a
+// function, to be named with SelectBuilder.Window.
+func WindowSpec() WindowBuilder {
+	return WindowBuilder{}
+}
+
+// RowNumber returns a WindowBuilder calling ROW_NUMBER().
+func RowNumber() WindowBuilder {
+	return Over("ROW_NUMBER()")
+}
+
+// Rank returns a WindowBuilder calling RANK().
+func Rank() WindowBuilder {
+	return Over("RANK()")
+}
+
+// DenseRank returns a WindowBuilder calling DENSE_RANK().
+func DenseRank() WindowBuilder {
+	return Over("DENSE_RANK()")
+}
+
+// Ntile returns a WindowBuilder calling NTILE(buckets).
+func Ntile(buckets uint64) WindowBuilder {
+	return Over(fmt.Sprintf("NTILE(%d)", buckets))
+}
+
+// Lag returns a WindowBuilder calling LAG(expr, offset), the value of expr
+// offset rows before the current row. An optional def is bound as the value
+// to use when there is no such row.
+func Lag(expr string, offset uint64, def ...interface{}) WindowBuilder {
+	return Over(offsetFunction("LAG", expr, offset, def))
+}
+
+// Lead returns a WindowBuilder calling LEAD(expr, offset), the value of expr
+// offset rows after the current row. An optional def is bound as the value to
+// use when there is no such row.
+func Lead(expr string, offset uint64, def ...interface{}) WindowBuilder {
+	return Over(offsetFunction("LEAD", expr, offset, def))
+}
+
+func offsetFunction(name, expr string, offset uint64, def []interface{}) Sqlizer {
+	switch len(def) {
+	case 0:
+		return Expr(fmt.Sprintf("%s(%s, %d)", name, expr, offset))
+	case 1:
+		return Expr(fmt.Sprintf("%s(%s, %d, ?)", name, expr, offset), def[0])
+	default:
+		return errSqlizer{fmt.Errorf("%s takes a single default value, not %d", name, len(def))}
+	}
+}
+
+// errSqlizer is a Sqlizer failing with err.
+type errSqlizer struct {
+	err error
+}
+
+func (e errSqlizer) ToSql() (string, []interface{}, error) {
+	return "", nil, e.err
+}
+
+// Window makes the window extend the named window defined with
+// SelectBuilder.Window, e.g. OVER w or OVER (w ORDER BY ...).
+func (b WindowBuilder) Window(name string) WindowBuilder {
+	return builder.Set(b, "Base", name).(WindowBuilder)
+}
+
+// PartitionBy adds PARTITION BY expressions to the window.
+func (b WindowBuilder) PartitionBy(partitionBys ...string) WindowBuilder {
+	for _, partitionBy := range partitionBys {
+		b = builder.Append(b, "PartitionBys", newPart(partitionBy)).(WindowBuilder)
+	}
+	return b
+}
+
+// OrderByClause adds an ORDER BY clause to the window.
+func (b WindowBuilder) OrderByClause(pred interface{}, args ...interface{}) WindowBuilder {
+	return builder.Append(b, "OrderByParts", newPart(pred, args...)).(WindowBuilder)
+}
+
+// OrderBy adds ORDER BY expressions to the window.
+func (b WindowBuilder) OrderBy(orderBys ...string) WindowBuilder {
+	for _, orderBy := range orderBys {
+		b = b.OrderByClause(orderBy)
+	}
+	return b
+}
+
+// Frame sets the frame clause of the window, e.g.
+//   Frame("ROWS BETWEEN ? PRECEDING AND CURRENT ROW", 6)
+func (b WindowBuilder) Frame(frame string, args ...interface{}) WindowBuilder {
+	return builder.Set(b, "Frame", Expr(frame, args...)).(WindowBuilder)
+}
+
+// RowsBetween sets a ROWS BETWEEN start AND end frame clause, with start and
+// end e.g. "UNBOUNDED PRECEDING", "3 PRECEDING" or "CURRENT ROW".
+func (b WindowBuilder) RowsBetween(start, end string) WindowBuilder {
+	return b.Frame(fmt.Sprintf("ROWS BETWEEN %s AND %s", start, end))
+}
+
+// RangeBetween sets a RANGE BETWEEN start AND end frame clause.
+func (b WindowBuilder) RangeBetween(start, end string) WindowBuilder {
+	return b.Frame(fmt.Sprintf("RANGE BETWEEN %s AND %s", start, end))
+}
+
+// namedWindow is a window definition of the WINDOW clause of a query.
+type namedWindow struct {
+	name string
+	spec WindowBuilder
+}
+
+func (w namedWindow) ToSql() (string, []interface{}, error) {
+	return w.toSqlDialect(nil)
+}
+
+func (w namedWindow) toSqlDialect(d Dialect) (string, []interface{}, error) {
+	if len(w.name) == 0 {
+		return "", nil, errors.New("named windows must have a name")
+	}
+	if builder.GetStruct(w.spec).(windowData).Function != nil {
+		return "", nil, fmt.Errorf("window %s must be a WindowSpec, not a window function", w.name)
+	}
+	specSql, specArgs, err := w.spec.toSqlDialect(d)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s AS %s", w.name, specSql), specArgs, nil
+}
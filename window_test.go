@@ -0,0 +1,108 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowFunctions(t *testing.T) {
+	tests := []struct {
+		window Sqlizer
+		sql    string
+	}{
+		{RowNumber().OrderBy("id"), "ROW_NUMBER() OVER (ORDER BY id)"},
+		{Rank().PartitionBy("a", "b").OrderBy("c DESC"), "RANK() OVER (PARTITION BY a, b ORDER BY c DESC)"},
+		{DenseRank().OrderBy("score"), "DENSE_RANK() OVER (ORDER BY score)"},
+		{Ntile(4).OrderBy("score"), "NTILE(4) OVER (ORDER BY score)"},
+		{Lag("price", 1).OrderBy("day"), "LAG(price, 1) OVER (ORDER BY day)"},
+		{Lead("price", 2).OrderBy("day"), "LEAD(price, 2) OVER (ORDER BY day)"},
+		{Over("COUNT(*)"), "COUNT(*) OVER ()"},
+		{Over("SUM(amount)").PartitionBy("account_id").OrderBy("day").RowsBetween("UNBOUNDED PRECEDING", "CURRENT ROW"),
+			"SUM(amount) OVER (PARTITION BY account_id ORDER BY day ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)"},
+		{Over("AVG(amount)").OrderBy("day").RangeBetween("INTERVAL '7' DAY PRECEDING", "CURRENT ROW"),
+			"AVG(amount) OVER (ORDER BY day RANGE BETWEEN INTERVAL '7' DAY PRECEDING AND CURRENT ROW)"},
+		{RowNumber().Window("w"), "ROW_NUMBER() OVER w"},
+		{Over("SUM(x)").Window("w").OrderBy("y"), "SUM(x) OVER (w ORDER BY y)"},
+		{WindowSpec().PartitionBy("a"), "(PARTITION BY a)"},
+	}
+	for _, test := range tests {
+		sql, args, err := test.window.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.sql, sql)
+		assert.Empty(t, args)
+	}
+}
+
+func TestWindowFunctionArgs(t *testing.T) {
+	w := Lag("price", 1, 0).
+		PartitionBy("product_id").
+		OrderByClause("day = ? DESC", "today").
+		Frame("ROWS BETWEEN ? PRECEDING AND CURRENT ROW", 6)
+
+	sql, args, err := Select("day").
+		Column(Alias(w, "prev_price")).
+		From("prices").
+		Where("price > ?", 10).
+		OrderByClause(Over("SUM(?)", 2).OrderBy("day")).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT day, " +
+		"(LAG(price, 1, $1) OVER (PARTITION BY product_id ORDER BY day = $2 DESC ROWS BETWEEN $3 PRECEDING AND CURRENT ROW)) AS prev_price " +
+		"FROM prices WHERE price > $4 ORDER BY SUM($5) OVER (ORDER BY day)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{0, "today", 6, 10, 2}, args)
+
+	_, _, err = Lag("price", 1, 0, 1).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderWindow(t *testing.T) {
+	sql, args, err := Select("id").
+		Column(Alias(RowNumber().Window("w"), "rn")).
+		Column(Alias(Over("SUM(amount)").Window("w").RowsBetween("1 PRECEDING", "1 FOLLOWING"), "total")).
+		From("payments").
+		GroupBy("id", "account_id", "day", "amount").
+		Having("amount > ?", 1).
+		Window("w", WindowSpec().PartitionBy("account_id").OrderByClause("day <> ?", "today")).
+		Window("w2", WindowSpec().Window("w")).
+		OrderBy("id").
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT id, (ROW_NUMBER() OVER w) AS rn, " +
+		"(SUM(amount) OVER (w ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING)) AS total " +
+		"FROM payments GROUP BY id, account_id, day, amount HAVING amount > ? " +
+		"WINDOW w AS (PARTITION BY account_id ORDER BY day <> ?), w2 AS (w) ORDER BY id"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, "today"}, args)
+}
+
+func TestSelectBuilderWindowErr(t *testing.T) {
+	_, _, err := Select("id").From("t").Window("w", RowNumber()).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("id").From("t").Window("", WindowSpec()).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderWindowDialect(t *testing.T) {
+	b := Select("id").
+		Column(Alias(RowNumber().OrderByClause(SortColumn{Column: "day"}.NullsLast()), "rn")).
+		From("payments").
+		Window("w", WindowSpec().OrderByClause(SortColumn{Column: "amount", Desc: true}.NullsFirst()))
+
+	sql, _, err := b.Dialect(PostgreSQL).ToSql()
+	assert.NoError(t, err)
+	expectedSql := "SELECT id, (ROW_NUMBER() OVER (ORDER BY day ASC NULLS LAST)) AS rn " +
+		"FROM payments WINDOW w AS (ORDER BY amount DESC NULLS FIRST)"
+	assert.Equal(t, expectedSql, sql)
+
+	sql, _, err = b.Dialect(MySQL).ToSql()
+	assert.NoError(t, err)
+	expectedSql = "SELECT id, (ROW_NUMBER() OVER (ORDER BY CASE WHEN day IS NULL THEN 1 ELSE 0 END, day ASC)) AS rn " +
+		"FROM payments WINDOW w AS (ORDER BY CASE WHEN amount IS NULL THEN 0 ELSE 1 END, amount DESC)"
+	assert.Equal(t, expectedSql, sql)
+}